@@ -0,0 +1,195 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AttributeKind is the kind of value a note attribute holds.
+type AttributeKind string
+
+const (
+	AttributeString AttributeKind = "string"
+	AttributeBool   AttributeKind = "bool"
+	AttributeInt    AttributeKind = "int"
+)
+
+// Attribute describes a key=value attribute allowed in the header of a
+// release note block, after its type:
+//
+//	```release-note:bug component=storage issue=4567 security=true
+type Attribute struct {
+	// Name is the key of the attribute.
+	Name string
+
+	// Kind is the kind of value the attribute holds. Defaults to
+	// AttributeString.
+	Kind AttributeKind
+
+	// Values lists the values the attribute may have. If it is empty, any
+	// value of the right kind is allowed.
+	Values []string
+
+	// Pattern, if set, must match the whole value.
+	Pattern *regexp.Regexp
+
+	// Required attributes must be set on every note.
+	Required bool
+}
+
+// DefaultAttributes are the attributes allowed when a repository does not
+// configure its own. An "issue" attribute always replaces the issue of the
+// note, which otherwise is the name of its entry file.
+var DefaultAttributes = []Attribute{
+	{Name: "issue"},
+	{Name: "component"},
+	{Name: "security", Kind: AttributeBool},
+	{Name: "cve", Pattern: regexp.MustCompile(`^CVE-\d{4}-\d{4,}$`)},
+}
+
+// AttributeConfig declares an attribute allowed in release note headers.
+// Its fields are described on Attribute; Pattern must match the whole value.
+type AttributeConfig struct {
+	Name     string   `hcl:"name,label" yaml:"name"`
+	Type     string   `hcl:"type,optional" yaml:"type"`
+	Values   []string `hcl:"values,optional" yaml:"values"`
+	Pattern  string   `hcl:"pattern,optional" yaml:"pattern"`
+	Required bool     `hcl:"required,optional" yaml:"required"`
+}
+
+// NewAttribute returns the attribute declared by c.
+func NewAttribute(c AttributeConfig) (Attribute, error) {
+	a := Attribute{
+		Name:     c.Name,
+		Kind:     AttributeKind(c.Type),
+		Values:   c.Values,
+		Required: c.Required,
+	}
+	switch a.Kind {
+	case "":
+		a.Kind = AttributeString
+	case AttributeString, AttributeBool, AttributeInt:
+	default:
+		return Attribute{}, fmt.Errorf("attribute %q: unknown type %q, must be one of [string bool int]", c.Name, c.Type)
+	}
+	if c.Pattern != "" {
+		re, err := regexp.Compile(`^(?:` + c.Pattern + `)$`)
+		if err != nil {
+			return Attribute{}, fmt.Errorf("attribute %q: invalid pattern: %w", c.Name, err)
+		}
+		a.Pattern = re
+	}
+	return a, nil
+}
+
+// Check returns an error describing why value isn't a valid value of the
+// attribute, or nil if it is.
+func (a Attribute) Check(value string) error {
+	switch a.Kind {
+	case AttributeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return errors.New("must be true or false")
+		}
+	case AttributeInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return errors.New("must be an integer")
+		}
+	}
+	if len(a.Values) > 0 && !contains(a.Values, value) {
+		return fmt.Errorf("must be one of %v", a.Values)
+	}
+	if a.Pattern != nil && !a.Pattern.MatchString(value) {
+		return fmt.Errorf("must match %s", a.Pattern)
+	}
+	return nil
+}
+
+// attributeNames returns the names of attrs.
+func attributeNames(attrs []Attribute) []string {
+	res := make([]string, 0, len(attrs))
+	for _, a := range attrs {
+		res = append(res, a.Name)
+	}
+	return res
+}
+
+// headerField is a whitespace-separated field in the header of a release
+// note block.
+type headerField struct {
+	text string
+
+	// offset is the byte offset of the field in the entry body.
+	offset int
+}
+
+// headerFields splits header, found at offset in the entry body, into
+// whitespace-separated fields. Whitespace inside double quotes doesn't
+// separate fields.
+func headerFields(header string, offset int) []headerField {
+	var res []headerField
+	start := -1
+	quoted := false
+	for i := 0; i < len(header); i++ {
+		c := header[i]
+		switch {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case !quoted && (c == ' ' || c == '\t'):
+			if start >= 0 {
+				res = append(res, headerField{text: header[start:i], offset: offset + start})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		res = append(res, headerField{text: header[start:], offset: offset + start})
+	}
+	return res
+}
+
+// headerAttribute is a key=value attribute in the header of a release note
+// block.
+type headerAttribute struct {
+	// text is the field the attribute was parsed from.
+	text       string
+	key, value string
+
+	// offset is the byte offset of the attribute in the entry body.
+	offset int
+
+	// malformed is set if the field isn't a key=value pair, or its value
+	// is badly quoted.
+	malformed bool
+}
+
+// parseHeaderAttribute parses a key=value field. The value may be double
+// quoted, with Go escapes.
+func parseHeaderAttribute(f headerField) headerAttribute {
+	key, value, ok := strings.Cut(f.text, "=")
+	a := headerAttribute{text: f.text, key: key, value: value, offset: f.offset}
+	if !ok || key == "" {
+		a.malformed = true
+		return a
+	}
+	if strings.HasPrefix(value, `"`) {
+		v, err := strconv.Unquote(value)
+		if err != nil {
+			a.malformed = true
+			return a
+		}
+		a.value = v
+	}
+	return a
+}
@@ -1,46 +1,15 @@
 // Copyright IBM Corp. 2020, 2025
 // SPDX-License-Identifier: MPL-2.0
 
+// Command changelog-check is the same as "changelog check".
 package main
 
 import (
-	"io"
-	"log"
 	"os"
 
-	"github.com/hashicorp/go-changelog"
+	"github.com/hashicorp/go-changelog/internal/cli"
 )
 
 func main() {
-	var err error
-
-	// default to reading from stdin
-	r := os.Stdin
-
-	// read from file arg instead if provided
-	// TODO: add --help text for [file] arg handling
-	filepath := ""
-	if len(os.Args) > 1 {
-		filepath = os.Args[1]
-		r, err = os.Open(filepath)
-		if err != nil {
-			log.Fatalf("error opening %s", filepath)
-		}
-	}
-
-	b, err := io.ReadAll(r)
-	if err != nil {
-		if filepath != "" {
-			log.Fatalf("error reading from %s", filepath)
-		}
-		log.Fatalf("error reading from stdin")
-	}
-
-	entry := changelog.Entry{
-		Body: string(b),
-	}
-
-	if err := entry.Validate(); err != nil {
-		log.Fatalf("%s", err)
-	}
+	os.Exit(cli.RunCommand("changelog-check", "check", os.Args[1:]))
 }
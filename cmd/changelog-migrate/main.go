@@ -0,0 +1,15 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Command changelog-migrate is the same as "changelog migrate".
+package main
+
+import (
+	"os"
+
+	"github.com/hashicorp/go-changelog/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.RunCommand("changelog-migrate", "migrate", os.Args[1:]))
+}
@@ -0,0 +1,15 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Command changelog-verify is the same as "changelog verify".
+package main
+
+import (
+	"os"
+
+	"github.com/hashicorp/go-changelog/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.RunCommand("changelog-verify", "verify", os.Args[1:]))
+}
@@ -0,0 +1,16 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Command changelog generates and checks changelogs from changelog entry
+// files.
+package main
+
+import (
+	"os"
+
+	"github.com/hashicorp/go-changelog/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Main(os.Args[1:]))
+}
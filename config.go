@@ -0,0 +1,454 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsimple"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultEntriesDir is the conventional directory holding changelog
+	// entry files, relative to the root of the repository.
+	DefaultEntriesDir = ".changelog"
+
+	// ConfigFileName is the name of the configuration file looked up inside
+	// DefaultEntriesDir.
+	ConfigFileName = "config.hcl"
+)
+
+// ConfigFileNames are the names of the configuration files looked up inside
+// DefaultEntriesDir, in order. YAML config files have the same fields as
+// HCL ones, with the labels of blocks as their name field.
+var ConfigFileNames = []string{ConfigFileName, "config.yaml", "config.yml"}
+
+// Config holds the project-level settings shared by the changelog-*
+// commands. It is usually loaded from .changelog/config.hcl, or
+// .changelog/config.yaml.
+type Config struct {
+	// EntriesDir is the directory, relative to Root, containing the
+	// changelog entry files.
+	EntriesDir string `hcl:"entries_dir,optional" yaml:"entries_dir"`
+
+	// EntryPattern is the naming scheme of entry files, described on
+	// EntryNaming. Defaults to DefaultEntryPattern.
+	EntryPattern string `hcl:"entry_pattern,optional" yaml:"entry_pattern"`
+
+	// IssueURL is the URL of an issue or pull request, with "{issue}"
+	// standing in for its identifier.
+	IssueURL string `hcl:"issue_url,optional" yaml:"issue_url"`
+
+	// TagPattern is the glob release tags match, used to find the previous
+	// release. Defaults to DefaultTagPattern.
+	TagPattern string `hcl:"tag_pattern,optional" yaml:"tag_pattern"`
+
+	// Subcategories lists the services or areas of the codebase that
+	// entries may be filed under.
+	Subcategories []string `hcl:"subcategories,optional" yaml:"subcategories"`
+
+	// SubcategoryPattern is the regular expression matching the
+	// subcategory prefix of a note body, with the subcategory in its first
+	// capture group. Defaults to DefaultSubcategoryPattern.
+	SubcategoryPattern string `hcl:"subcategory_pattern,optional" yaml:"subcategory_pattern"`
+
+	// Types lists the allowed changelog entry types. When empty,
+	// DefaultTypes is used.
+	Types []*TypeConfig `hcl:"type,block" yaml:"type"`
+
+	// Attributes lists the attributes allowed in the headers of release
+	// note blocks. When empty, DefaultAttributes is used.
+	Attributes []*AttributeConfig `hcl:"attribute,block" yaml:"attribute"`
+
+	Templates *TemplatesConfig `hcl:"templates,block" yaml:"templates"`
+
+	// Rules enables and configures the built-in lint rules checked by
+	// changelog-check.
+	Rules []*RuleConfig `hcl:"rule,block" yaml:"rule"`
+
+	// Projects lists the projects of a repository holding several, each
+	// with its own entries, release tags and changelog.
+	Projects []*ProjectConfig `hcl:"project,block" yaml:"project"`
+
+	// Root is the directory relative paths in the config are resolved
+	// against.
+	Root string `yaml:"-"`
+
+	// Path is the file the config was loaded from, or empty if no config
+	// file was found.
+	Path string `yaml:"-"`
+
+	types         *TypeRegistry
+	subcategoryRE *regexp.Regexp
+	attributes    []Attribute
+	naming        *EntryNaming
+}
+
+// TypeConfig declares a single changelog entry type. Its fields are
+// described on Type.
+type TypeConfig struct {
+	Name       string   `hcl:"name,label" yaml:"name"`
+	Aliases    []string `hcl:"aliases,optional" yaml:"aliases"`
+	Heading    string   `hcl:"heading,optional" yaml:"heading"`
+	Order      int      `hcl:"order,optional" yaml:"order"`
+	Deprecated bool     `hcl:"deprecated,optional" yaml:"deprecated"`
+}
+
+// TemplatesConfig holds the paths of the templates used by the commands,
+// relative to the config's Root.
+type TemplatesConfig struct {
+	// Preset is the name of the built-in changelog-build templates used
+	// when Changelog or ReleaseNote aren't set.
+	Preset string `hcl:"preset,optional" yaml:"preset"`
+
+	Changelog   string `hcl:"changelog,optional" yaml:"changelog"`
+	ReleaseNote string `hcl:"release_note,optional" yaml:"release_note"`
+	Entry       string `hcl:"entry,optional" yaml:"entry"`
+}
+
+// ProjectConfig declares a project of a repository holding several, such as
+// a Go module in a monorepo. Its settings override those of the Config for
+// the project.
+type ProjectConfig struct {
+	Name string `hcl:"name,label" yaml:"name"`
+
+	// EntriesDir is the directory, relative to the config's Root, containing
+	// the changelog entry files of the project.
+	EntriesDir string `hcl:"entries_dir" yaml:"entries_dir"`
+
+	// TagPrefix is prepended to the config's TagPattern to match the
+	// release tags of the project, such as "sdk/" for sdk/v1.2.0 tags.
+	TagPrefix string `hcl:"tag_prefix,optional" yaml:"tag_prefix"`
+
+	// Changelog is the path of the changelog file of the project, relative
+	// to the config's Root.
+	Changelog string `hcl:"changelog,optional" yaml:"changelog"`
+
+	// Templates overrides the templates of the config for the project.
+	Templates *TemplatesConfig `hcl:"templates,block" yaml:"templates"`
+}
+
+// DefaultConfig returns the Config used when no config file exists, rooted
+// at dir.
+func DefaultConfig(dir string) *Config {
+	return &Config{
+		EntriesDir: DefaultEntriesDir,
+		Templates:  &TemplatesConfig{},
+		Root:       dir,
+	}
+}
+
+// LoadConfig reads the config file at path, which is YAML if its extension
+// is .yaml or .yml, and HCL otherwise.
+func LoadConfig(path string) (*Config, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := DefaultConfig(configRoot(abs))
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(src))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+		if err := checkYAMLConfig(cfg); err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	default:
+		if err := hclsimple.Decode(filepath.Base(path), src, nil, cfg); err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	}
+	cfg.Path = abs
+	if cfg.EntriesDir == "" {
+		cfg.EntriesDir = DefaultEntriesDir
+	}
+	if cfg.Templates == nil {
+		cfg.Templates = &TemplatesConfig{}
+	}
+	if len(cfg.Types) > 0 {
+		types := make([]Type, 0, len(cfg.Types))
+		for _, t := range cfg.Types {
+			types = append(types, Type{
+				Name:       t.Name,
+				Aliases:    t.Aliases,
+				Heading:    t.Heading,
+				Order:      t.Order,
+				Deprecated: t.Deprecated,
+			})
+		}
+		cfg.types, err = NewTypeRegistry(types...)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	}
+	if cfg.SubcategoryPattern != "" {
+		cfg.subcategoryRE, err = regexp.Compile(cfg.SubcategoryPattern)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: invalid subcategory_pattern: %w", path, err)
+		}
+		if cfg.subcategoryRE.NumSubexp() < 1 {
+			return nil, fmt.Errorf("error parsing config file %s: subcategory_pattern must capture the subcategory in a group", path)
+		}
+	}
+	if cfg.EntryPattern != "" {
+		cfg.naming, err = NewEntryNaming(cfg.EntryPattern)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	}
+	projects := map[string]bool{}
+	for _, p := range cfg.Projects {
+		if projects[p.Name] {
+			return nil, fmt.Errorf("error parsing config file %s: project %q declared more than once", path, p.Name)
+		}
+		projects[p.Name] = true
+	}
+	seen := map[string]bool{}
+	for _, c := range cfg.Attributes {
+		if seen[c.Name] {
+			return nil, fmt.Errorf("error parsing config file %s: attribute %q declared more than once", path, c.Name)
+		}
+		seen[c.Name] = true
+		a, err := NewAttribute(*c)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+		cfg.attributes = append(cfg.attributes, a)
+	}
+	if _, err := cfg.Linter(); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// FindConfig walks up from dir looking for a .changelog/config.hcl,
+// config.yaml or config.yml file, in that order, and returns its path. The
+// search stops at the root of the git repository containing dir. If no
+// config file is found, an empty path is returned.
+func FindConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, name := range ConfigFileNames {
+			path := filepath.Join(dir, DefaultEntriesDir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// OpenConfig loads the config file at path. If path is empty, the config is
+// discovered by walking up from dir, falling back to DefaultConfig rooted at
+// the root of the git repository containing dir, or at dir if it isn't in
+// one, if none is found.
+func OpenConfig(path, dir string) (*Config, error) {
+	if path == "" {
+		var err error
+		path, err = FindConfig(dir)
+		if err != nil {
+			return nil, err
+		}
+		if path == "" {
+			return DefaultConfig(repositoryRoot(dir)), nil
+		}
+	}
+	return LoadConfig(path)
+}
+
+// TypeRegistry returns the registry of the allowed changelog entry types.
+func (c *Config) TypeRegistry() *TypeRegistry {
+	if c.types == nil {
+		return defaultTypes()
+	}
+	return c.types
+}
+
+// NoteParser returns a NoteParser for the types, subcategory pattern,
+// subcategories, attributes and entry naming scheme of the config.
+func (c *Config) NoteParser() *NoteParser {
+	return &NoteParser{
+		Types:              c.TypeRegistry(),
+		SubcategoryPattern: c.subcategoryRE,
+		Subcategories:      c.Subcategories,
+		Attributes:         c.attributes,
+		Naming:             c.EntryNaming(),
+	}
+}
+
+// ProjectNames returns the names of the projects of the config, in the order
+// they are declared.
+func (c *Config) ProjectNames() []string {
+	res := make([]string, 0, len(c.Projects))
+	for _, p := range c.Projects {
+		res = append(res, p.Name)
+	}
+	return res
+}
+
+// Project returns the config of the project name: a copy of the config with
+// the entries directory, tag pattern and templates of the project, and no
+// projects. An error is returned if the project isn't declared.
+func (c *Config) Project(name string) (*Config, error) {
+	for _, p := range c.Projects {
+		if p.Name != name {
+			continue
+		}
+		res := *c
+		res.Projects = nil
+		res.EntriesDir = p.EntriesDir
+		tagPattern := c.TagPattern
+		if tagPattern == "" {
+			tagPattern = DefaultTagPattern
+		}
+		res.TagPattern = p.TagPrefix + tagPattern
+		var templates TemplatesConfig
+		if c.Templates != nil {
+			templates = *c.Templates
+		}
+		if p.Templates != nil {
+			if p.Templates.Preset != "" {
+				templates.Preset = p.Templates.Preset
+			}
+			if p.Templates.Changelog != "" {
+				templates.Changelog = p.Templates.Changelog
+			}
+			if p.Templates.ReleaseNote != "" {
+				templates.ReleaseNote = p.Templates.ReleaseNote
+			}
+			if p.Templates.Entry != "" {
+				templates.Entry = p.Templates.Entry
+			}
+		}
+		res.Templates = &templates
+		return &res, nil
+	}
+	return nil, fmt.Errorf("unknown project %q, must be one of %v", name, c.ProjectNames())
+}
+
+// EntryNaming returns the naming scheme of the entry files.
+func (c *Config) EntryNaming() *EntryNaming {
+	if c.naming == nil {
+		return DefaultEntryNaming
+	}
+	return c.naming
+}
+
+// ResolvePath returns path resolved against the config's Root. Empty and
+// absolute paths are returned unchanged.
+func (c *Config) ResolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.Root, path)
+}
+
+// IssueURLFor returns the URL of issue, or an empty string if no issue URL
+// pattern is configured.
+func (c *Config) IssueURLFor(issue string) string {
+	if c.IssueURL == "" {
+		return ""
+	}
+	return strings.ReplaceAll(c.IssueURL, "{issue}", issue)
+}
+
+// repositoryRoot returns the root of the git repository containing dir, or
+// dir if it isn't in one.
+func repositoryRoot(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	for d := abs; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
+
+// IsConfigFile reports whether name, the path of a file in an entries
+// directory, has the name of a config file, and so isn't an entry.
+func IsConfigFile(name string) bool {
+	base := path.Base(filepath.ToSlash(name))
+	for _, n := range ConfigFileNames {
+		if base == n {
+			return true
+		}
+	}
+	return false
+}
+
+// checkYAMLConfig checks the fields of cfg, decoded from YAML, that HCL
+// requires: the names of blocks, and the entries directory of projects.
+func checkYAMLConfig(cfg *Config) error {
+	for _, t := range cfg.Types {
+		if t == nil || t.Name == "" {
+			return errors.New("every type must have a name")
+		}
+	}
+	for _, a := range cfg.Attributes {
+		if a == nil || a.Name == "" {
+			return errors.New("every attribute must have a name")
+		}
+	}
+	for _, r := range cfg.Rules {
+		if r == nil || r.Name == "" {
+			return errors.New("every rule must have a name")
+		}
+	}
+	for _, p := range cfg.Projects {
+		if p == nil || p.Name == "" {
+			return errors.New("every project must have a name")
+		}
+		if p.EntriesDir == "" {
+			return fmt.Errorf("project %q must set entries_dir", p.Name)
+		}
+	}
+	return nil
+}
+
+// configRoot returns the directory a config file at path is rooted at: the
+// parent of the .changelog directory it lives in, or its own directory
+// otherwise.
+func configRoot(path string) string {
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) == DefaultEntriesDir {
+		return filepath.Dir(dir)
+	}
+	return dir
+}
@@ -0,0 +1,192 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DiagnosticSeverity is how serious a Diagnostic is.
+type DiagnosticSeverity string
+
+const (
+	SeverityError   DiagnosticSeverity = "error"
+	SeverityWarning DiagnosticSeverity = "warning"
+)
+
+// Diagnostic is a problem found in a changelog entry.
+type Diagnostic struct {
+	Code     EntryErrorCode
+	Severity DiagnosticSeverity
+	Message  string
+
+	// Rule is the name of the lint rule that reported the diagnostic, if
+	// any.
+	Rule string
+
+	// Note is the index of the release note block the diagnostic is about,
+	// counting blocks in the order they appear in the entry body, or -1 if
+	// it is about the whole entry or a fence that isn't a valid block.
+	Note int
+
+	// Line and Column locate the problem in the entry body, starting at 1.
+	// Column counts bytes. Both are 0 if the diagnostic is about the whole
+	// entry.
+	Line, Column int
+}
+
+// String formats the diagnostic as "LINE:COLUMN: SEVERITY: MESSAGE", leaving
+// out the position if it has none.
+func (d Diagnostic) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", d.Line, d.Column, d.Severity, d.Message)
+}
+
+// Diagnostics is a list of problems found in a changelog entry.
+type Diagnostics []Diagnostic
+
+// HasErrors reports whether any of the diagnostics is an error.
+func (ds Diagnostics) HasErrors() bool {
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// WithCode returns the diagnostics with the given code.
+func (ds Diagnostics) WithCode(code EntryErrorCode) Diagnostics {
+	var res Diagnostics
+	for _, d := range ds {
+		if d.Code == code {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+// Sort sorts the diagnostics by their position in the entry body, keeping
+// the order of diagnostics at the same position.
+func (ds Diagnostics) Sort() {
+	sort.SliceStable(ds, func(i, j int) bool {
+		if ds[i].Line != ds[j].Line {
+			return ds[i].Line < ds[j].Line
+		}
+		return ds[i].Column < ds[j].Column
+	})
+}
+
+// Diagnose checks that the entry body contains properly formatted changelog
+// notes whose types are all in reg, and returns every problem found, in the
+// order they appear in the body.
+func (e *Entry) Diagnose(reg *TypeRegistry) Diagnostics {
+	return (&NoteParser{Types: reg}).Diagnose(e)
+}
+
+// Diagnose is like Entry.Diagnose, but checks the types of the notes against
+// p.Types, their header attributes against p.Attributes and, if
+// p.Subcategories isn't empty, that their subcategories are all in it.
+func (p *NoteParser) Diagnose(e *Entry) Diagnostics {
+	reg := p.types()
+	attrs := p.attributes()
+	blocks, fenceDiags := scanNoteBlocks(*e)
+	if len(blocks) == 0 && len(fenceDiags) == 0 {
+		return Diagnostics{{
+			Code:     EntryErrorNotFound,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("no changelog entry found in: %s", e.Body),
+			Note:     -1,
+		}}
+	}
+
+	res := fenceDiags
+	for i, b := range blocks {
+		add := func(code EntryErrorCode, severity DiagnosticSeverity, offset int, format string, args ...interface{}) {
+			line, col := position(e.Body, offset)
+			res = append(res, Diagnostic{
+				Code:     code,
+				Severity: severity,
+				Message:  fmt.Sprintf(format, args...),
+				Note:     i,
+				Line:     line,
+				Column:   col,
+			})
+		}
+		typ := b.note.Type
+		switch t, ok := reg.Lookup(typ); {
+		case typ == "":
+			add(EntryErrorMissingType, SeverityError, b.start, "missing changelog type: please use one of the configured changelog entry types: %v", reg.Names(false))
+		case !ok:
+			add(EntryErrorUnknownTypes, SeverityError, b.typeStart, "unknown changelog type %q: please use only the configured changelog entry types: %v", typ, reg.Names(false))
+		case t.Deprecated:
+			add(EntryErrorDeprecatedType, SeverityWarning, b.typeStart, "changelog type %q is deprecated: please use one of the configured changelog entry types: %v", typ, reg.Names(false))
+		}
+		if b.note.Body == "" {
+			add(EntryErrorEmptyBody, SeverityError, b.start, "empty changelog note")
+		}
+		seen := map[string]bool{}
+		for _, a := range b.attributes {
+			attr, known := lookupAttribute(attrs, a.key)
+			switch {
+			case a.malformed:
+				add(EntryErrorInvalidAttribute, SeverityError, a.offset, "malformed attribute %q: attributes must be written as key=value", a.text)
+			case seen[a.key]:
+				add(EntryErrorInvalidAttribute, SeverityError, a.offset, "attribute %q set more than once", a.key)
+			case !known:
+				add(EntryErrorUnknownAttribute, SeverityError, a.offset, "unknown attribute %q: please use only the configured attributes: %v", a.key, attributeNames(attrs))
+			default:
+				if err := attr.Check(a.value); err != nil {
+					add(EntryErrorInvalidAttribute, SeverityError, a.offset, "invalid value %q for attribute %q: %s", a.value, a.key, err)
+				}
+			}
+			seen[a.key] = true
+		}
+		for _, attr := range attrs {
+			if attr.Required && !seen[attr.Name] {
+				add(EntryErrorMissingAttribute, SeverityError, b.start, "missing attribute %q: please set it in the header of the changelog note", attr.Name)
+			}
+		}
+		if sub, _ := p.SplitSubcategory(b.note.Body); sub != "" && len(p.Subcategories) > 0 && !contains(p.Subcategories, sub) {
+			add(EntryErrorUnknownSubcategory, SeverityError, b.bodyStart, "unknown subcategory %q: please use only the configured subcategories: %v", sub, p.Subcategories)
+		}
+	}
+	res.Sort()
+	return res
+}
+
+// position returns the line and column, starting at 1, of the byte offset
+// in s, or 0, 0 if offset is negative.
+func position(s string, offset int) (int, int) {
+	if offset < 0 {
+		return 0, 0
+	}
+	before := s[:offset]
+	line := strings.Count(before, "\n") + 1
+	return line, offset - strings.LastIndex(before, "\n")
+}
+
+// contains reports whether list contains s.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupAttribute returns the attribute of attrs with the given name.
+func lookupAttribute(attrs []Attribute, name string) (Attribute, bool) {
+	for _, a := range attrs {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Attribute{}, false
+}
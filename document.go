@@ -0,0 +1,185 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// versionHeadingRE matches the heading of a version section in a changelog
+// file, such as "## 1.2.0 (October 18, 2026)" or "## [1.2.0] - 2026-10-18".
+var versionHeadingRE = regexp.MustCompile(`^##[ \t]+\[?(v?[0-9][^\]\s]*)\]?(.*)$`)
+
+// checksumRE matches the comment changelog-build leaves after the heading of
+// the sections it generates.
+var checksumRE = regexp.MustCompile(`^<!-- changelog-build checksum: (sha256:[0-9a-f]{64}) -->[ \t]*$`)
+
+// Document is a changelog file, such as CHANGELOG.md, split into version
+// sections.
+type Document struct {
+	src []byte
+
+	// Sections holds the version sections of the document, in the order
+	// they appear.
+	Sections []*Section
+}
+
+// Section is the part of a Document under a version heading, up to the next
+// version heading or the end of the document.
+type Section struct {
+	// Version is the version in the heading, as written.
+	Version string
+
+	// Title is the rest of the heading, such as "(October 18, 2026)".
+	Title string
+
+	// Checksum is the checksum recorded when the section was generated,
+	// or empty if it wasn't generated.
+	Checksum string
+
+	// Body is the content of the section after its heading and checksum.
+	Body []byte
+
+	// Line is the line number of the heading, starting at 1.
+	Line int
+
+	start, end int
+}
+
+// ParseDocument splits src into version sections. Headings inside fenced
+// code blocks, such as examples in release notes, don't start sections.
+func ParseDocument(src []byte) *Document {
+	d := &Document{src: src}
+	var fences []fence
+	for _, f := range scanFences(string(src)) {
+		if !f.malformed {
+			fences = append(fences, f)
+		}
+	}
+	var cur *Section
+	bodyStart := 0
+	offset := 0
+	for i, line := range bytes.SplitAfter(src, []byte("\n")) {
+		for len(fences) > 0 && fences[0].end < offset {
+			fences = fences[1:]
+		}
+		inFence := len(fences) > 0 && fences[0].start < offset && offset <= fences[0].end
+		text := strings.TrimRight(string(line), "\r\n")
+		if m := versionHeadingRE.FindStringSubmatch(text); m != nil && !inFence {
+			if cur != nil {
+				cur.end = offset
+				cur.Body = src[bodyStart:offset]
+			}
+			cur = &Section{
+				Version: m[1],
+				Title:   strings.TrimSpace(m[2]),
+				Line:    i + 1,
+				start:   offset,
+			}
+			d.Sections = append(d.Sections, cur)
+			bodyStart = offset + len(line)
+		} else if cur != nil && bodyStart == offset && cur.Checksum == "" {
+			if m := checksumRE.FindStringSubmatch(text); m != nil {
+				cur.Checksum = m[1]
+				bodyStart = offset + len(line)
+			}
+		}
+		offset += len(line)
+	}
+	if cur != nil {
+		cur.end = len(src)
+		cur.Body = src[bodyStart:]
+	}
+	return d
+}
+
+// Section returns the section for version, or nil if there is none.
+// Versions are compared as semantic versions when possible, so "v1.2.0"
+// matches a "1.2.0" heading.
+func (d *Document) Section(version string) *Section {
+	for _, s := range d.Sections {
+		if sameVersion(s.Version, version) {
+			return s
+		}
+	}
+	return nil
+}
+
+// Edited reports whether the section was written or changed by hand: that
+// is, it has no checksum, or its body no longer matches its checksum.
+func (s *Section) Edited() bool {
+	return s.Checksum == "" || s.Checksum != sectionChecksum(s.Body)
+}
+
+// SetSection returns the contents of the document with the section for
+// version replaced by a generated section with the given title and body.
+// If there is no section for version, it is inserted before the first
+// section with a lower version, or at the end of the document. The rest of
+// the document is left unchanged.
+func (d *Document) SetSection(version, title string, body []byte) []byte {
+	section := RenderSection(version, title, body)
+	start, end := len(d.src), len(d.src)
+	if s := d.Section(version); s != nil {
+		start, end = s.start, s.end
+	} else {
+		for _, s := range d.Sections {
+			if compareVersions(s.Version, version) < 0 {
+				start, end = s.start, s.start
+				break
+			}
+		}
+	}
+	if end == len(d.src) {
+		section = append(bytes.TrimRight(section, "\n"), '\n')
+	}
+	res := make([]byte, 0, len(d.src)+len(section))
+	res = append(res, d.src[:start]...)
+	if start > 0 && !bytes.HasSuffix(res, []byte("\n\n")) {
+		if !bytes.HasSuffix(res, []byte("\n")) {
+			res = append(res, '\n')
+		}
+		res = append(res, '\n')
+	}
+	res = append(res, section...)
+	return append(res, d.src[end:]...)
+}
+
+// RenderSection returns a version section with the given heading title and
+// body, including the checksum used to detect later edits to it.
+func RenderSection(version, title string, body []byte) []byte {
+	heading := "## " + version
+	if title != "" {
+		heading += " " + title
+	}
+	return []byte(fmt.Sprintf("%s\n<!-- changelog-build checksum: %s -->\n%s\n\n", heading, sectionChecksum(body), bytes.TrimSpace(body)))
+}
+
+// sectionChecksum returns the checksum of a section body, ignoring leading
+// and trailing whitespace.
+func sectionChecksum(body []byte) string {
+	sum := sha256.Sum256(bytes.TrimSpace(body))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// sameVersion reports whether a and b are the same version.
+func sameVersion(a, b string) bool {
+	va, vb := TagVersion(a), TagVersion(b)
+	if va == "" || vb == "" {
+		return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+	}
+	return semver.Compare(va, vb) == 0
+}
+
+// compareVersions compares a and b as semantic versions. Versions that
+// can't be parsed sort before all others.
+func compareVersions(a, b string) int {
+	return semver.Compare(TagVersion(a), TagVersion(b))
+}
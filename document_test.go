@@ -0,0 +1,209 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDocument(t *testing.T) {
+	generated := string(RenderSection("1.1.0", "(Unreleased)", []byte("* Fixed a crash\n")))
+
+	// parsed is the part of a section the tests compare.
+	type parsed struct {
+		version, title, body string
+		line                 int
+		generated            bool
+	}
+	cases := []struct {
+		name string
+		src  string
+		want []parsed
+	}{
+		{
+			name: "no sections",
+			src:  "# Changelog\n\nNothing yet.\n",
+		},
+		{
+			name: "hand written sections",
+			src:  "# Changelog\n\n## 1.1.0 (October 18, 2026)\n\n* Fixed\n\n## [1.0.0] - 2026-01-01\n\n* Added\n",
+			want: []parsed{
+				{version: "1.1.0", title: "(October 18, 2026)", body: "\n* Fixed\n\n", line: 3},
+				{version: "1.0.0", title: "- 2026-01-01", body: "\n* Added\n", line: 7},
+			},
+		},
+		{
+			name: "generated section",
+			src:  generated + "## 1.0.0\n",
+			want: []parsed{
+				{version: "1.1.0", title: "(Unreleased)", body: "* Fixed a crash\n\n", line: 1, generated: true},
+				{version: "1.0.0", line: 5},
+			},
+		},
+		{
+			name: "checksum not after the heading",
+			src:  "## 1.1.0\n\n" + strings.SplitAfter(generated, "\n")[1],
+			want: []parsed{
+				{version: "1.1.0", body: "\n" + strings.SplitAfter(generated, "\n")[1], line: 1},
+			},
+		},
+		{
+			name: "headings in fenced code blocks",
+			src:  "## 1.1.0\n\n* Changed the format:\n  ```markdown\n  ## 1.0.0\n  ```\n~~~\n## 0.9.0\n~~~\n## 1.0.0\n",
+			want: []parsed{
+				{version: "1.1.0", body: "\n* Changed the format:\n  ```markdown\n  ## 1.0.0\n  ```\n~~~\n## 0.9.0\n~~~\n", line: 1},
+				{version: "1.0.0", line: 10},
+			},
+		},
+		{
+			name: "heading in an unterminated fenced code block",
+			src:  "## 1.1.0\n```\n## 1.0.0\n",
+			want: []parsed{
+				{version: "1.1.0", body: "```\n## 1.0.0\n", line: 1},
+			},
+		},
+		{
+			name: "crlf line endings",
+			src:  "## 1.1.0\r\n* Fixed\r\n## 1.0.0\r\n",
+			want: []parsed{
+				{version: "1.1.0", body: "* Fixed\r\n", line: 1},
+				{version: "1.0.0", line: 3},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []parsed
+			for _, s := range ParseDocument([]byte(tc.src)).Sections {
+				got = append(got, parsed{
+					version:   s.Version,
+					title:     s.Title,
+					body:      string(s.Body),
+					line:      s.Line,
+					generated: s.Checksum != "",
+				})
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ParseDocument(%q) = %+v, want %+v", tc.src, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSectionEdited(t *testing.T) {
+	generated := string(RenderSection("1.1.0", "", []byte("* Fixed a crash\n")))
+	cases := []struct {
+		name string
+		src  string
+		want bool
+	}{
+		{name: "generated", src: generated, want: false},
+		{name: "generated followed by a section", src: generated + "## 1.0.0\n", want: false},
+		{name: "whitespace added", src: generated + "\n\n", want: false},
+		{name: "note edited", src: strings.Replace(generated, "crash", "panic", 1), want: true},
+		{name: "note added", src: generated + "* Added a flag\n", want: true},
+		{name: "hand written", src: "## 1.1.0\n\n* Fixed a crash\n", want: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := ParseDocument([]byte(tc.src)).Section("1.1.0")
+			if s == nil {
+				t.Fatalf("no 1.1.0 section in %q", tc.src)
+			}
+			if got := s.Edited(); got != tc.want {
+				t.Errorf("Edited() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetSection(t *testing.T) {
+	body := []byte("* Fixed a crash\n")
+	section := func(version string) string {
+		return string(RenderSection(version, "(Unreleased)", body))
+	}
+	cases := []struct {
+		name    string
+		src     string
+		version string
+		want    string
+	}{
+		{
+			name:    "empty document",
+			version: "1.1.0",
+			want:    strings.TrimSuffix(section("1.1.0"), "\n"),
+		},
+		{
+			name:    "append after the title",
+			src:     "# Changelog\n",
+			version: "1.1.0",
+			want:    "# Changelog\n\n" + strings.TrimSuffix(section("1.1.0"), "\n"),
+		},
+		{
+			name:    "append without a trailing newline",
+			src:     "# Changelog",
+			version: "1.1.0",
+			want:    "# Changelog\n\n" + strings.TrimSuffix(section("1.1.0"), "\n"),
+		},
+		{
+			name:    "insert before a lower version",
+			src:     "# Changelog\n\n## 1.0.0\n\n* Added\n",
+			version: "1.1.0",
+			want:    "# Changelog\n\n" + section("1.1.0") + "## 1.0.0\n\n* Added\n",
+		},
+		{
+			name:    "insert by semantic version",
+			src:     "## 1.10.0\n\n* Ten\n\n## 1.2.0\n\n* Two\n",
+			version: "v1.9.0",
+			want:    "## 1.10.0\n\n* Ten\n\n" + section("v1.9.0") + "## 1.2.0\n\n* Two\n",
+		},
+		{
+			name:    "insert after every version",
+			src:     "## 1.2.0\n\n* Two\n",
+			version: "1.1.0",
+			want:    "## 1.2.0\n\n* Two\n\n" + strings.TrimSuffix(section("1.1.0"), "\n"),
+		},
+		{
+			name:    "replace",
+			src:     "## 1.2.0\n\n* Two\n\n## 1.1.0\n\n* One\n\n## 1.0.0\n\n* Zero\n",
+			version: "1.1.0",
+			want:    "## 1.2.0\n\n* Two\n\n" + section("1.1.0") + "## 1.0.0\n\n* Zero\n",
+		},
+		{
+			name:    "replace the last section",
+			src:     "## 1.1.0\n\n* One\n",
+			version: "v1.1.0",
+			want:    strings.TrimSuffix(section("v1.1.0"), "\n"),
+		},
+		{
+			name:    "replace a generated section",
+			src:     string(RenderSection("1.1.0", "(Unreleased)", []byte("* Old\n"))) + "## 1.0.0\n",
+			version: "1.1.0",
+			want:    section("1.1.0") + "## 1.0.0\n",
+		},
+		{
+			name:    "heading in a fenced code block",
+			src:     "## 1.2.0\n\n```\n## 1.0.0\n```\n",
+			version: "1.1.0",
+			want:    "## 1.2.0\n\n```\n## 1.0.0\n```\n\n" + strings.TrimSuffix(section("1.1.0"), "\n"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := string(ParseDocument([]byte(tc.src)).SetSection(tc.version, "(Unreleased)", body))
+			if got != tc.want {
+				t.Errorf("SetSection(%q) =\n%s\nwant\n%s", tc.version, got, tc.want)
+			}
+			if !strings.HasSuffix(got, "\n") || strings.HasSuffix(got, "\n\n") {
+				t.Errorf("SetSection(%q) = %q, want a single trailing newline", tc.version, got)
+			}
+			s := ParseDocument([]byte(got)).Section(tc.version)
+			if s == nil || s.Edited() {
+				t.Errorf("SetSection(%q) = %q, want an unedited generated section", tc.version, got)
+			}
+		})
+	}
+}
@@ -4,19 +4,29 @@
 package changelog
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"path"
 	"path/filepath"
 	"sort"
 	"sync"
 	"time"
 
-	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/storage/memory"
 )
 
+// TypeValues lists the names of the default changelog entry types. Types
+// added to it are accepted by TypeValid, Entry.Validate and the other
+// functions using the default types.
+//
+// Deprecated: use DefaultTypes, or the TypeRegistry from a repository's
+// Config, instead.
 var TypeValues = []string{
 	"enhancement",
 	"improvement",
@@ -35,10 +45,38 @@ var TypeValues = []string{
 type Entry struct {
 	Issue string
 	Body  string
-	Date  time.Time
-	Hash  string
+
+	// Format is the format of Body. If it is empty, the format is selected
+	// by the extension of Issue, as done by EntryFormatOf.
+	Format EntryFormat
+
+	// Date and Hash identify the last commit that changed the entry.
+	Date time.Time
+	Hash string
+
+	// FirstSeenDate and FirstSeenHash identify the first commit that
+	// changed the entry. When the entry comes from a diff, this is the
+	// commit that introduced it within the diffed range.
+	FirstSeenDate time.Time
+	FirstSeenHash string
+
+	// Status describes how the entry changed, when it comes from a diff.
+	Status EntryStatus
+
+	// PreviousBody is the body of the entry before it was modified or
+	// removed.
+	PreviousBody string
 }
 
+// EntryStatus describes how an entry changed between two revisions.
+type EntryStatus string
+
+const (
+	EntryAdded    EntryStatus = "added"
+	EntryModified EntryStatus = "modified"
+	EntryRemoved  EntryStatus = "removed"
+)
+
 // EntryList provides thread-safe operations on a list of Entry values
 type EntryList struct {
 	mu sync.RWMutex
@@ -48,8 +86,20 @@ type EntryList struct {
 type EntryErrorCode string
 
 const (
-	EntryErrorNotFound     EntryErrorCode = "NOT_FOUND"
-	EntryErrorUnknownTypes EntryErrorCode = "UNKNOWN_TYPES"
+	EntryErrorNotFound           EntryErrorCode = "NOT_FOUND"
+	EntryErrorUnknownTypes       EntryErrorCode = "UNKNOWN_TYPES"
+	EntryErrorMissingType        EntryErrorCode = "MISSING_TYPE"
+	EntryErrorEmptyBody          EntryErrorCode = "EMPTY_BODY"
+	EntryErrorDeprecatedType     EntryErrorCode = "DEPRECATED_TYPE"
+	EntryErrorUnknownSubcategory EntryErrorCode = "UNKNOWN_SUBCATEGORY"
+	EntryErrorUnknownAttribute   EntryErrorCode = "UNKNOWN_ATTRIBUTE"
+	EntryErrorInvalidAttribute   EntryErrorCode = "INVALID_ATTRIBUTE"
+	EntryErrorMissingAttribute   EntryErrorCode = "MISSING_ATTRIBUTE"
+	EntryErrorMalformedFence     EntryErrorCode = "MALFORMED_FENCE"
+	EntryErrorUnterminatedFence  EntryErrorCode = "UNTERMINATED_FENCE"
+	EntryErrorMalformedEntry     EntryErrorCode = "MALFORMED_ENTRY"
+	EntryErrorInvalidName        EntryErrorCode = "INVALID_NAME"
+	EntryErrorRule               EntryErrorCode = "RULE"
 )
 
 type EntryValidationError struct {
@@ -63,26 +113,31 @@ func (e *EntryValidationError) Error() string {
 }
 
 // Validates that an Entry body contains properly formatted changelog notes
+// using the default types.
 func (e *Entry) Validate() *EntryValidationError {
-	notes := NotesFromEntry(*e)
+	return e.ValidateWith(defaultTypes())
+}
 
-	if len(notes) < 1 {
+// ValidateWith validates that an Entry body contains properly formatted
+// changelog notes whose types are all in reg. Only the first kind of error
+// found is returned; use Diagnose to get every problem with the entry.
+func (e *Entry) ValidateWith(reg *TypeRegistry) *EntryValidationError {
+	diags := e.Diagnose(reg)
+	if d := diags.WithCode(EntryErrorNotFound); len(d) > 0 {
 		return &EntryValidationError{
-			message: fmt.Sprintf("no changelog entry found in: %s", string(e.Body)),
+			message: d[0].Message,
 			Code:    EntryErrorNotFound,
 		}
 	}
 
 	var unknownTypes []string
-	for _, note := range notes {
-		if !TypeValid(note.Type) {
-			unknownTypes = append(unknownTypes, note.Type)
-		}
+	blocks := noteBlocks(*e)
+	for _, d := range diags.WithCode(EntryErrorUnknownTypes) {
+		unknownTypes = append(unknownTypes, blocks[d.Note].note.Type)
 	}
-
 	if len(unknownTypes) > 0 {
 		return &EntryValidationError{
-			message: fmt.Sprintf("unknown changelog types %v: please use only the configured changelog entry types: %v", unknownTypes, TypeValues),
+			message: fmt.Sprintf("unknown changelog types %v: please use only the configured changelog entry types: %v", unknownTypes, reg.Names(false)),
 			Code:    EntryErrorUnknownTypes,
 			Details: map[string]interface{}{
 				"unknownTypes": unknownTypes,
@@ -90,6 +145,14 @@ func (e *Entry) Validate() *EntryValidationError {
 		}
 	}
 
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return &EntryValidationError{
+				message: d.Message,
+				Code:    d.Code,
+			}
+		}
+	}
 	return nil
 }
 
@@ -142,6 +205,23 @@ func (el *EntryList) Len() int {
 	return len(el.es)
 }
 
+// WithStatus returns a new EntryList holding the entries with one of the
+// given statuses.
+func (el *EntryList) WithStatus(statuses ...EntryStatus) *EntryList {
+	el.mu.RLock()
+	defer el.mu.RUnlock()
+	res := NewEntryList(len(el.es))
+	for _, e := range el.es {
+		for _, s := range statuses {
+			if e.Status == s {
+				res.es = append(res.es, e)
+				break
+			}
+		}
+	}
+	return res
+}
+
 // SortByIssue does an in-place sort of the entries by their issue number.
 func (el *EntryList) SortByIssue() {
 	el.mu.Lock()
@@ -162,134 +242,409 @@ type changelog struct {
 // ref1 and ref2 should be valid git refs as strings and dir should be a valid
 // directory path in the repository.
 //
-// The function calculates the diff by first checking out ref2 and collecting
-// the set of all entries in dir. It then checks out ref1 and subtracts the
-// entries found in dir. The resulting set of entries is then filtered to
-// exclude any entries that came before the commit date of ref1.
+// The function calculates the diff by reading the commit tree of ref2 and
+// collecting the set of all entries in dir and its subdirectories, then
+// subtracting the entries found in dir in the commit tree of ref1, unless
+// ref1 is "-". The Issue of each entry is the slash-separated path of its
+// file relative to dir. Only added entries are returned; use DiffChanges to
+// also get the entries that were modified or removed.
 //
 // It clones the repository into memory for processing, so makes no changes
 // to the local filesystem, but may use significant memory for large repositories.
 //
-// Along the way, if any git or filesystem interactions fail, an error is returned.
+// Along the way, if any git interactions fail, an error is returned.
 func Diff(repo, ref1, ref2, dir string) (*EntryList, error) {
-	r, err := git.Clone(memory.NewStorage(), memfs.New(), &git.CloneOptions{
+	entries, err := DiffChanges(repo, ref1, ref2, dir)
+	if err != nil {
+		return nil, err
+	}
+	return entries.WithStatus(EntryAdded), nil
+}
+
+// DiffChanges is like Diff, but returns every entry that was added, modified
+// or removed between ref1 and ref2, with its Status set accordingly.
+func DiffChanges(repo, ref1, ref2, dir string) (*EntryList, error) {
+	r, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
 		URL: repo,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return diff(r, ref1, ref2, dir, true)
+	return diff(context.Background(), r, ref1, ref2, dir)
 }
 
-// DiffLocal returns a list of entries that are present in ref1 but not
-// in ref2 within the local git repository at repoPath.
-//
-// It calculates the list the same way as Diff, but operates on the local
-// filesystem rather than cloning the repo into memory.
+// DiffOptions controls how DiffWithOptions clones the repository and which
+// entries it returns.
+type DiffOptions struct {
+	// Auth is used to authenticate with the remote repository, for example
+	// a *http.BasicAuth holding a token or a *ssh.PublicKeys.
+	Auth transport.AuthMethod
+
+	// ReferenceName is the branch or tag to clone. Defaults to the remote's
+	// HEAD.
+	ReferenceName plumbing.ReferenceName
+
+	// SingleBranch limits the clone to ReferenceName, plus the tags
+	// pointing into its history.
+	SingleBranch bool
+
+	// Depth limits the clone to the given number of commits from the tip of
+	// each branch. Zero means the full history.
+	Depth int
+
+	// ShallowSince limits the clone to the history since the given time,
+	// like git clone --shallow-since. As the git transport used doesn't
+	// support it natively, the repository is cloned with increasing depths
+	// until its oldest commit predates ShallowSince, starting from Depth
+	// or 64 commits.
+	ShallowSince time.Time
+
+	// Tags controls which tags are fetched. Defaults to the tags pointing
+	// into the fetched history.
+	Tags git.TagMode
+
+	// Progress receives the human readable progress sent by the server.
+	Progress io.Writer
+
+	// Changes includes the entries that were modified or removed between
+	// the two refs in the result, like DiffChanges.
+	Changes bool
+}
+
+// DiffWithOptions is like Diff, but clones the repository according to opts
+// and stops when ctx is cancelled. ref1 and ref2 must be present in the
+// cloned history, so shallow or single branch clones need to include both.
+func DiffWithOptions(ctx context.Context, repo, ref1, ref2, dir string, opts DiffOptions) (*EntryList, error) {
+	r, err := cloneWithOptions(ctx, repo, opts)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := diff(ctx, r, ref1, ref2, dir)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.Changes {
+		entries = entries.WithStatus(EntryAdded)
+	}
+	return entries, nil
+}
+
+// CloneRepository clones repo into memory according to opts, like
+// DiffWithOptions, so that the same clone can be used to find release tags
+// and to diff entries with DiffRepository.
+func CloneRepository(ctx context.Context, repo string, opts DiffOptions) (*git.Repository, error) {
+	return cloneWithOptions(ctx, repo, opts)
+}
+
+func cloneWithOptions(ctx context.Context, repo string, opts DiffOptions) (*git.Repository, error) {
+	co := &git.CloneOptions{
+		URL:           repo,
+		Auth:          opts.Auth,
+		ReferenceName: opts.ReferenceName,
+		SingleBranch:  opts.SingleBranch,
+		Depth:         opts.Depth,
+		Tags:          opts.Tags,
+		Progress:      opts.Progress,
+	}
+	if opts.ShallowSince.IsZero() {
+		return git.CloneContext(ctx, memory.NewStorage(), nil, co)
+	}
+	if co.Depth <= 0 {
+		co.Depth = 64
+	}
+	for {
+		r, err := git.CloneContext(ctx, memory.NewStorage(), nil, co)
+		if err != nil {
+			return nil, err
+		}
+		shallow, err := r.Storer.Shallow()
+		if err != nil {
+			return nil, err
+		}
+		deepEnough := true
+		for _, h := range shallow {
+			c, err := r.CommitObject(h)
+			if err != nil {
+				return nil, err
+			}
+			if !c.Committer.When.Before(opts.ShallowSince) {
+				deepEnough = false
+				break
+			}
+		}
+		if deepEnough {
+			return r, nil
+		}
+		co.Depth *= 2
+	}
+}
+
+// DiffLocal returns a list of entries that are present in ref2 but not
+// in ref1 within the local git repository at repoPath.
 //
-// Since it operates on the local filesystem, it will modify repository state.
-// Namely, it checks out each ref, leaving whatever branch or other ref
-// is currently checked out. Use with caution, preferably on a clean clone.
+// It calculates the list the same way as Diff, but reads the objects of the
+// local repository rather than cloning the repo into memory. The worktree,
+// index and HEAD of the repository are left untouched.
 func DiffLocal(repoPath, ref1, ref2, dir string) (*EntryList, error) {
+	entries, err := DiffLocalChanges(repoPath, ref1, ref2, dir)
+	if err != nil {
+		return nil, err
+	}
+	return entries.WithStatus(EntryAdded), nil
+}
+
+// DiffLocalChanges is like DiffLocal, but returns every entry that was added,
+// modified or removed between ref1 and ref2, with its Status set
+// accordingly.
+func DiffLocalChanges(repoPath, ref1, ref2, dir string) (*EntryList, error) {
 	r, err := git.PlainOpen(repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("error opening repository at %q: %w", repoPath, err)
 	}
-	return diff(r, ref1, ref2, dir, false)
+	return diff(context.Background(), r, ref1, ref2, dir)
 }
 
-func diff(r *git.Repository, ref1, ref2, dir string, forceCheckout bool) (*EntryList, error) {
-	rev2, err := r.ResolveRevision(plumbing.Revision(ref2))
+// DiffRepository is like DiffChanges, but diffs the entries of r, a
+// repository that was already opened or cloned.
+func DiffRepository(ctx context.Context, r *git.Repository, ref1, ref2, dir string) (*EntryList, error) {
+	return diff(ctx, r, ref1, ref2, dir)
+}
+
+func diff(ctx context.Context, r *git.Repository, ref1, ref2, dir string) (*EntryList, error) {
+	res, err := diffRanges(ctx, r, ref2, []DiffRange{{Since: ref1, Dir: dir}})
 	if err != nil {
-		return nil, fmt.Errorf("could not resolve revision %s: %w", ref2, err)
+		return nil, err
 	}
-	var rev1 *plumbing.Hash
-	if ref1 != "-" {
-		rev1, err = r.ResolveRevision(plumbing.Revision(ref1))
-		if err != nil {
-			return nil, fmt.Errorf("could not resolve revision %s: %w", ref1, err)
-		}
+	return res[0], nil
+}
+
+// DiffRange is a directory of entries and the ref they are diffed since,
+// such as the previous release of one project in a repository.
+type DiffRange struct {
+	// Since is a ref to the last commit of the previous release, or "-"
+	// to include every entry.
+	Since string
+
+	Dir string
+
+	// Naming, when set, ignores the files of Dir that don't follow it
+	// rather than diffing them as entries.
+	Naming *EntryNaming
+}
+
+// DiffLocalRanges is like DiffLocalChanges, but diffs the entries of each
+// of ranges up to ref2 at once, sharing a single walk of the history of
+// ref2. The entries are returned in the order of ranges.
+func DiffLocalRanges(repoPath, ref2 string, ranges []DiffRange) ([]*EntryList, error) {
+	r, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening repository at %q: %w", repoPath, err)
+	}
+	return diffRanges(context.Background(), r, ref2, ranges)
+}
+
+// DiffRangesWithOptions is like DiffLocalRanges, but clones the repository
+// according to opts, like DiffWithOptions.
+func DiffRangesWithOptions(ctx context.Context, repo, ref2 string, ranges []DiffRange, opts DiffOptions) ([]*EntryList, error) {
+	r, err := cloneWithOptions(ctx, repo, opts)
+	if err != nil {
+		return nil, err
 	}
-	wt, err := r.Worktree()
+	res, err := diffRanges(ctx, r, ref2, ranges)
 	if err != nil {
 		return nil, err
 	}
-	if err := wt.Checkout(&git.CheckoutOptions{
-		Hash:  *rev2,
-		Force: forceCheckout,
-	}); err != nil {
-		return nil, fmt.Errorf("could not checkout repository at %s: %w", ref2, err)
+	if !opts.Changes {
+		for i, entries := range res {
+			res[i] = entries.WithStatus(EntryAdded)
+		}
 	}
-	entriesAfterFI, err := wt.Filesystem.ReadDir(dir)
+	return res, nil
+}
+
+// DiffRepositoryRanges is like DiffLocalRanges, but diffs the entries of r,
+// a repository that was already opened or cloned.
+func DiffRepositoryRanges(ctx context.Context, r *git.Repository, ref2 string, ranges []DiffRange) ([]*EntryList, error) {
+	return diffRanges(ctx, r, ref2, ranges)
+}
+
+func diffRanges(ctx context.Context, r *git.Repository, ref2 string, ranges []DiffRange) ([]*EntryList, error) {
+	rev2, err := r.ResolveRevision(plumbing.Revision(ref2))
 	if err != nil {
-		return nil, fmt.Errorf("could not read repository directory %s: %w", dir, err)
-	}
-	// a set of all entries at rev2 (this release); the set of entries at ref1
-	// will then be subtracted from it to arrive at a set of 'candidate' entries.
-	entryCandidates := make(map[string]bool, len(entriesAfterFI))
-	for _, i := range entriesAfterFI {
-		entryCandidates[i.Name()] = true
-	}
-	if rev1 != nil {
-		err = wt.Checkout(&git.CheckoutOptions{
-			Hash:  *rev1,
-			Force: forceCheckout,
-		})
-		if err != nil {
-			return nil, err
+		return nil, fmt.Errorf("could not resolve revision %s: %w", ref2, err)
+	}
+
+	type rangeDiff struct {
+		dir                   string
+		treeBefore, treeAfter *object.Tree
+		changed               map[string]EntryStatus
+	}
+	diffs := make([]rangeDiff, len(ranges))
+	hranges := make([]historyRange, len(ranges))
+	for i, rg := range ranges {
+		dir := path.Clean(filepath.ToSlash(rg.Dir))
+		var rev1 *plumbing.Hash
+		if rg.Since != "-" {
+			rev1, err = r.ResolveRevision(plumbing.Revision(rg.Since))
+			if err != nil {
+				return nil, fmt.Errorf("could not resolve revision %s: %w", rg.Since, err)
+			}
 		}
-		entriesBeforeFI, err := wt.Filesystem.ReadDir(dir)
+		treeAfter, err := entriesTree(r, *rev2, dir)
 		if err != nil {
-			return nil, fmt.Errorf("could not read repository directory %s: %w", dir, err)
+			return nil, fmt.Errorf("could not read repository directory %s at %s: %w", dir, ref2, err)
 		}
-		for _, i := range entriesBeforeFI {
-			delete(entryCandidates, i.Name())
+		treeBefore := &object.Tree{}
+		if rev1 != nil {
+			treeBefore, err = entriesTree(r, *rev1, dir)
+			if errors.Is(err, object.ErrDirectoryNotFound) {
+				// the entries directory was added after ref1, so every
+				// entry is new
+				treeBefore = &object.Tree{}
+			} else if err != nil {
+				return nil, fmt.Errorf("could not read repository directory %s at %s: %w", dir, rg.Since, err)
+			}
 		}
-		// checkout rev2 so that we can read files later
-		if err := wt.Checkout(&git.CheckoutOptions{
-			Hash:  *rev2,
-			Force: forceCheckout,
-		}); err != nil {
-			return nil, fmt.Errorf("could not checkout repository at %s: %w", ref2, err)
-		}
-	}
 
-	entries := NewEntryList(len(entryCandidates))
-	for name := range entryCandidates {
-		fp := filepath.Join(dir, name)
-		f, err := wt.Filesystem.Open(fp)
+		// compare the entries at rev2 (this release) with the entries at
+		// rev1 to arrive at the set of entries that changed.
+		changed := map[string]EntryStatus{}
+		before, err := treeFileHashes(treeBefore, rg.Naming)
 		if err != nil {
-			return nil, fmt.Errorf("error opening file at %s: %w", name, err)
+			return nil, fmt.Errorf("could not list repository directory %s at %s: %w", dir, rg.Since, err)
 		}
-		contents, err := ioutil.ReadAll(f)
-		f.Close()
+		after, err := treeFileHashes(treeAfter, rg.Naming)
 		if err != nil {
-			return nil, fmt.Errorf("error reading file at %s: %w", name, err)
+			return nil, fmt.Errorf("could not list repository directory %s at %s: %w", dir, ref2, err)
 		}
-		log, err := r.Log(&git.LogOptions{FileName: &fp})
-		if err != nil {
-			return nil, fmt.Errorf("error fetching git log for %s: %w", name, err)
+		for name, hash := range after {
+			prev, ok := before[name]
+			if !ok {
+				changed[name] = EntryAdded
+			} else if prev != hash {
+				changed[name] = EntryModified
+			}
+			delete(before, name)
 		}
-		lastChange, err := log.Next()
-		if err != nil {
-			return nil, fmt.Errorf("error fetching next git log: %w", err)
+		for name := range before {
+			changed[name] = EntryRemoved
 		}
-		entries.Append(&Entry{
-			Issue: name,
-			Body:  string(contents),
-			Date:  lastChange.Author.When,
-			Hash:  lastChange.Hash.String(),
-		})
-	}
-	entries.SortByIssue()
-	return entries, nil
+		candidates := make(map[string]bool, len(changed))
+		for name := range changed {
+			candidates[name] = true
+		}
+		diffs[i] = rangeDiff{dir: dir, treeBefore: treeBefore, treeAfter: treeAfter, changed: changed}
+		hranges[i] = historyRange{from: rev1, dir: dir, names: candidates}
+	}
+
+	histories, err := rangesHistory(ctx, r, *rev2, hranges)
+	if err != nil {
+		return nil, fmt.Errorf("error walking git history: %w", err)
+	}
+	var fallback *object.Commit
+	res := make([]*EntryList, len(ranges))
+	for i, d := range diffs {
+		entries := NewEntryList(len(d.changed))
+		for name, status := range d.changed {
+			entry := &Entry{
+				Issue:  name,
+				Status: status,
+			}
+			if status != EntryRemoved {
+				entry.Body, err = treeFileContents(d.treeAfter, name)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if status != EntryAdded {
+				entry.PreviousBody, err = treeFileContents(d.treeBefore, name)
+				if err != nil {
+					return nil, err
+				}
+			}
+			h, ok := histories[i][name]
+			if !ok {
+				// this shouldn't happen, as every candidate was changed
+				// somewhere in the range, but attribute it to ref2 rather
+				// than dropping it
+				if fallback == nil {
+					fallback, err = r.CommitObject(*rev2)
+					if err != nil {
+						return nil, err
+					}
+				}
+				h = &fileHistory{first: fallback, last: fallback}
+			}
+			entry.Date = h.last.Author.When
+			entry.Hash = h.last.Hash.String()
+			entry.FirstSeenDate = h.first.Author.When
+			entry.FirstSeenHash = h.first.Hash.String()
+			entries.Append(entry)
+		}
+		entries.SortByIssue()
+		res[i] = entries
+	}
+	return res, nil
 }
 
-func TypeValid(Type string) bool {
-	for _, a := range TypeValues {
-		if a == Type {
-			return true
+// treeFileHashes returns the hashes of the entry files in tree and its
+// subdirectories, by their slash-separated path relative to tree. Config
+// files and files without the extension of an entry file, such as
+// templates or a README, are skipped, as are files that don't follow
+// naming, if not nil.
+func treeFileHashes(tree *object.Tree, naming *EntryNaming) (map[string]plumbing.Hash, error) {
+	res := make(map[string]plumbing.Hash, len(tree.Entries))
+	err := tree.Files().ForEach(func(f *object.File) error {
+		if !f.Mode.IsFile() || !IsEntryFile(f.Name) || IsConfigFile(f.Name) {
+			return nil
 		}
+		if naming != nil {
+			if _, _, ok := naming.Match(f.Name); !ok {
+				return nil
+			}
+		}
+		res[f.Name] = f.Hash
+		return nil
+	})
+	return res, err
+}
+
+// treeFileContents returns the contents of the file name in tree.
+func treeFileContents(tree *object.Tree, name string) (string, error) {
+	f, err := tree.File(name)
+	if err != nil {
+		return "", fmt.Errorf("error opening file at %s: %w", name, err)
 	}
-	return false
+	contents, err := f.Contents()
+	if err != nil {
+		return "", fmt.Errorf("error reading file at %s: %w", name, err)
+	}
+	return contents, nil
+}
+
+// entriesTree returns the tree of dir in the commit identified by hash.
+func entriesTree(r *git.Repository, hash plumbing.Hash, dir string) (*object.Tree, error) {
+	commit, err := r.CommitObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	return commitDirTree(commit, dir)
+}
+
+// commitDirTree returns the tree of dir in c.
+func commitDirTree(c *object.Commit, dir string) (*object.Tree, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+	if dir == "." {
+		return tree, nil
+	}
+	return tree.Tree(dir)
+}
+
+// TypeValid reports whether Type is one of the default types, or of
+// TypeValues if it was changed.
+func TypeValid(Type string) bool {
+	return defaultTypes().Valid(Type)
 }
@@ -0,0 +1,169 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"strings"
+)
+
+// fence is a fenced code block in a Markdown document, as described by
+// CommonMark: an opening line of at least three backticks or tildes
+// followed by an info string, and a closing line of at least as many of the
+// same character. Unlike CommonMark, fences may be indented by any amount,
+// as they are inside list items; the indentation of the opening fence is
+// removed from its content lines.
+type fence struct {
+	// char is the fence character, '`' or '~', and length the number of
+	// them in the opening fence.
+	char   byte
+	length int
+
+	// indent is the width of the indentation of the opening fence.
+	indent int
+
+	// start is the byte offset of the opening fence, after its
+	// indentation.
+	start int
+
+	// info is the info string of the opening fence, and infoStart its byte
+	// offset.
+	info      string
+	infoStart int
+
+	// lines are the content lines of the block.
+	lines []fenceLine
+
+	// end is the byte offset of the closing fence, or of the end of the
+	// document if the block is unterminated.
+	end int
+
+	// closed is false if the block runs to the end of the document.
+	closed bool
+
+	// malformed is set for lines that look like opening fences but aren't
+	// one: two fence characters, or backticks followed by an info string
+	// containing a backtick. Malformed fences have no content.
+	malformed bool
+}
+
+// fenceLine is a content line of a fenced code block.
+type fenceLine struct {
+	// text is the line, without its line ending and the indentation of
+	// the opening fence.
+	text string
+
+	// offset is the byte offset of text.
+	offset int
+}
+
+// scanFences returns the fenced code blocks in src, and the malformed
+// fences found outside them, in the order they appear.
+func scanFences(src string) []fence {
+	var res []fence
+	var cur *fence
+	for offset := 0; offset < len(src); {
+		end := strings.IndexByte(src[offset:], '\n')
+		next := len(src)
+		if end < 0 {
+			end = len(src)
+		} else {
+			end += offset
+			next = end + 1
+		}
+		line := strings.TrimSuffix(src[offset:end], "\r")
+		width, n := indentation(line)
+		rest := line[n:]
+
+		if cur != nil {
+			if width < cur.indent+4 && isClosingFence(rest, cur.char, cur.length) {
+				cur.end = offset + n
+				cur.closed = true
+				res = append(res, *cur)
+				cur = nil
+			} else {
+				skip := stripIndent(line, cur.indent)
+				cur.lines = append(cur.lines, fenceLine{text: line[skip:], offset: offset + skip})
+			}
+			offset = next
+			continue
+		}
+
+		if c := firstByte(rest); c == '`' || c == '~' {
+			length := len(rest) - len(strings.TrimLeft(rest, string(c)))
+			afterFence := rest[length:]
+			info := strings.TrimSpace(afterFence)
+			f := fence{
+				char:      c,
+				length:    length,
+				indent:    width,
+				start:     offset + n,
+				info:      info,
+				infoStart: offset + n + length + len(afterFence) - len(strings.TrimLeft(afterFence, " \t")),
+			}
+			switch {
+			case length >= 3 && !(c == '`' && strings.ContainsRune(info, '`')):
+				cur = &f
+			case length >= 2:
+				f.malformed = true
+				f.end = offset + len(line)
+				res = append(res, f)
+			}
+		}
+		offset = next
+	}
+	if cur != nil {
+		cur.end = len(src)
+		res = append(res, *cur)
+	}
+	return res
+}
+
+// isClosingFence reports whether s, a line without its indentation, closes
+// a block opened by length fence characters c.
+func isClosingFence(s string, c byte, length int) bool {
+	n := len(s) - len(strings.TrimLeft(s, string(c)))
+	return n >= length && strings.TrimRight(s[n:], " \t") == ""
+}
+
+// indentation returns the width of the leading whitespace of line, with
+// tabs advancing to the next multiple of 4, and its length in bytes.
+func indentation(line string) (width, n int) {
+	for n < len(line) {
+		switch line[n] {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width, n
+		}
+		n++
+	}
+	return width, n
+}
+
+// stripIndent returns the number of bytes of leading whitespace to remove
+// from line to remove at most width columns of indentation.
+func stripIndent(line string, width int) int {
+	w, n := 0, 0
+	for n < len(line) && w < width {
+		switch line[n] {
+		case ' ':
+			w++
+		case '\t':
+			w += 4 - w%4
+		default:
+			return n
+		}
+		n++
+	}
+	return n
+}
+
+func firstByte(s string) byte {
+	if s == "" {
+		return 0
+	}
+	return s[0]
+}
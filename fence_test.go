@@ -0,0 +1,158 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanFences(t *testing.T) {
+	// scanned is the part of a fence the tests compare.
+	type scanned struct {
+		info      string
+		lines     []string
+		closed    bool
+		malformed bool
+	}
+
+	cases := []struct {
+		name string
+		src  string
+		want []scanned
+	}{
+		{
+			name: "backticks",
+			src:  "```release-note:bug\nFixed a crash\n```\n",
+			want: []scanned{{info: "release-note:bug", lines: []string{"Fixed a crash"}, closed: true}},
+		},
+		{
+			name: "tildes",
+			src:  "~~~release-note:bug\nFixed a crash\n~~~\n",
+			want: []scanned{{info: "release-note:bug", lines: []string{"Fixed a crash"}, closed: true}},
+		},
+		{
+			name: "longer closing fence",
+			src:  "```release-note:bug\nFixed a crash\n`````\n",
+			want: []scanned{{info: "release-note:bug", lines: []string{"Fixed a crash"}, closed: true}},
+		},
+		{
+			name: "shorter fence inside a longer one",
+			src:  "````release-note:note\nUse:\n```\nfoo\n```\n````\n",
+			want: []scanned{{info: "release-note:note", lines: []string{"Use:", "```", "foo", "```"}, closed: true}},
+		},
+		{
+			name: "other character inside a fence",
+			src:  "```\n~~~\n```\n",
+			want: []scanned{{lines: []string{"~~~"}, closed: true}},
+		},
+		{
+			name: "closing fence with an info string",
+			src:  "```release-note:bug\nFixed\n```go\n```\n",
+			want: []scanned{{info: "release-note:bug", lines: []string{"Fixed", "```go"}, closed: true}},
+		},
+		{
+			name: "indented fence",
+			src:  "* item\n  ```release-note:bug\n  Fixed a crash\n    indented\n  ```\n",
+			want: []scanned{{info: "release-note:bug", lines: []string{"Fixed a crash", "  indented"}, closed: true}},
+		},
+		{
+			name: "indented closing fence",
+			src:  "```release-note:bug\nFixed a crash\n   ```\n",
+			want: []scanned{{info: "release-note:bug", lines: []string{"Fixed a crash"}, closed: true}},
+		},
+		{
+			name: "closing fence indented as code",
+			src:  "```release-note:bug\nFixed a crash\n    ```\n",
+			want: []scanned{{info: "release-note:bug", lines: []string{"Fixed a crash", "    ```"}}},
+		},
+		{
+			name: "unterminated",
+			src:  "```release-note:bug\nFixed a crash\n",
+			want: []scanned{{info: "release-note:bug", lines: []string{"Fixed a crash"}}},
+		},
+		{
+			name: "unterminated by a shorter fence",
+			src:  "````release-note:bug\nFixed a crash\n```\n",
+			want: []scanned{{info: "release-note:bug", lines: []string{"Fixed a crash", "```"}}},
+		},
+		{
+			name: "two fence characters",
+			src:  "``release-note:bug\nFixed a crash\n",
+			want: []scanned{{info: "release-note:bug", malformed: true}},
+		},
+		{
+			name: "backtick in the info string",
+			src:  "```release-note:`bug`\nFixed a crash\n",
+			want: []scanned{{info: "release-note:`bug`", malformed: true}},
+		},
+		{
+			name: "crlf line endings",
+			src:  "```release-note:bug\r\nFixed a crash\r\n```\r\n",
+			want: []scanned{{info: "release-note:bug", lines: []string{"Fixed a crash"}, closed: true}},
+		},
+		{
+			name: "no fences",
+			src:  "Fixed a crash\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []scanned
+			for _, f := range scanFences(tc.src) {
+				s := scanned{info: f.info, closed: f.closed, malformed: f.malformed}
+				for _, l := range f.lines {
+					s.lines = append(s.lines, l.text)
+				}
+				got = append(got, s)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("scanFences(%q) = %+v, want %+v", tc.src, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNotesFromEntryFences(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "note after a code block quoting a note",
+			body: "Example:\n\n```\n```release-note:bug\nquoted\n```\n\n```release-note:bug\nFixed a crash\n```\n",
+			want: []string{"bug: Fixed a crash"},
+		},
+		{
+			name: "note quoted in a longer fence",
+			body: "````markdown\n```release-note:bug\nquoted\n```\n````\n",
+		},
+		{
+			name: "release-note text in another code block",
+			body: "```go\n// release-note:bug\nfmt.Println(\"release-note:bug\")\n```\n",
+		},
+		{
+			name: "tilde note containing a backtick fence",
+			body: "~~~release-note:note\nRun:\n```\nmake\n```\n~~~\n",
+			want: []string{"note: Run:\n```\nmake\n```"},
+		},
+		{
+			name: "indented note",
+			body: "* item\n  ```release-note:bug\n  Fixed a crash\n  ```\n",
+			want: []string{"bug: Fixed a crash"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, n := range NotesFromEntry(Entry{Issue: "1234", Body: tc.body}) {
+				got = append(got, n.Type+": "+n.Body)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("NotesFromEntry(%q) = %q, want %q", tc.body, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// EntryFormat is the format of an entry file.
+type EntryFormat string
+
+const (
+	// FormatText entries hold release note blocks in Markdown fenced code
+	// blocks.
+	FormatText EntryFormat = "text"
+
+	// FormatYAML, FormatJSON and FormatTOML entries hold a list of notes
+	// and their fields.
+	FormatYAML EntryFormat = "yaml"
+	FormatJSON EntryFormat = "json"
+	FormatTOML EntryFormat = "toml"
+)
+
+// EntryExtensions maps the extensions of entry files to their formats.
+var EntryExtensions = map[string]EntryFormat{
+	".txt":  FormatText,
+	".yaml": FormatYAML,
+	".yml":  FormatYAML,
+	".json": FormatJSON,
+	".toml": FormatTOML,
+}
+
+// EntryFormatOf returns the format of the entry file name, selected by its
+// extension. Files with other extensions are text.
+func EntryFormatOf(name string) EntryFormat {
+	if f, ok := EntryExtensions[strings.ToLower(path.Ext(name))]; ok {
+		return f
+	}
+	return FormatText
+}
+
+// IsEntryFile reports whether name has the extension of an entry file.
+func IsEntryFile(name string) bool {
+	_, ok := EntryExtensions[strings.ToLower(path.Ext(name))]
+	return ok
+}
+
+// TrimEntryExtension returns name without its extension, if it is the
+// extension of an entry file.
+func TrimEntryExtension(name string) string {
+	if IsEntryFile(name) {
+		return strings.TrimSuffix(name, path.Ext(name))
+	}
+	return name
+}
+
+// structuredEntry is the contents of a YAML, JSON or TOML entry file:
+//
+//	notes:
+//	  - type: bug
+//	    body: "storage: Fixed a crash when the bucket is empty"
+//	    attributes:
+//	      issue: 4567
+type structuredEntry struct {
+	Notes []structuredNote `json:"notes" yaml:"notes" toml:"notes"`
+}
+
+type structuredNote struct {
+	Type       string                 `json:"type" yaml:"type" toml:"type"`
+	Body       string                 `json:"body" yaml:"body" toml:"body"`
+	Attributes map[string]interface{} `json:"attributes,omitempty" yaml:"attributes,omitempty" toml:"attributes,omitempty"`
+}
+
+// decodeStructuredEntry decodes the body of an entry in format, rejecting
+// unknown fields. The offset of a syntax error is returned when known, or
+// -1.
+func decodeStructuredEntry(body string, format EntryFormat) (structuredEntry, int, error) {
+	var res structuredEntry
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(strings.NewReader(body))
+		dec.DisallowUnknownFields()
+		err := dec.Decode(&res)
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return res, int(syntaxErr.Offset), err
+		}
+		return res, -1, err
+	case FormatYAML:
+		dec := yaml.NewDecoder(strings.NewReader(body))
+		dec.KnownFields(true)
+		if err := dec.Decode(&res); err != nil && !errors.Is(err, io.EOF) {
+			return res, -1, err
+		}
+		return res, -1, nil
+	case FormatTOML:
+		md, err := toml.NewDecoder(bytes.NewReader([]byte(body))).Decode(&res)
+		if err != nil {
+			var parseErr toml.ParseError
+			if errors.As(err, &parseErr) {
+				return res, parseErr.Position.Start, err
+			}
+			return res, -1, err
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, 0, len(undecoded))
+			for _, k := range undecoded {
+				keys = append(keys, k.String())
+			}
+			return res, -1, fmt.Errorf("unknown fields %v", keys)
+		}
+		return res, -1, nil
+	default:
+		return res, -1, fmt.Errorf("unknown entry format %q", format)
+	}
+}
+
+// FormatEntry returns the body of a YAML, JSON or TOML entry holding the
+// notes of existing, the body of an entry in the same format, followed by
+// notes. Only the type, body and attributes of notes are written.
+func FormatEntry(existing string, notes []Note, format EntryFormat) (string, error) {
+	var entry structuredEntry
+	if strings.TrimSpace(existing) != "" {
+		var err error
+		entry, _, err = decodeStructuredEntry(existing, format)
+		if err != nil {
+			return "", fmt.Errorf("malformed %s changelog entry: %w", strings.ToUpper(string(format)), err)
+		}
+	}
+	for _, n := range notes {
+		sn := structuredNote{Type: n.Type, Body: n.Body}
+		if len(n.Attributes) > 0 {
+			sn.Attributes = make(map[string]interface{}, len(n.Attributes))
+			for k, v := range n.Attributes {
+				sn.Attributes[k] = v
+			}
+		}
+		entry.Notes = append(entry.Notes, sn)
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(entry)
+	case FormatYAML:
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		err = enc.Encode(entry)
+	case FormatTOML:
+		err = toml.NewEncoder(&buf).Encode(entry)
+	default:
+		err = fmt.Errorf("unknown entry format %q", format)
+	}
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// structuredNoteBlocks returns the notes in entry, a YAML, JSON or TOML
+// entry, along with an error if it can't be decoded. The notes have no
+// position in the entry body.
+func structuredNoteBlocks(entry Entry, format EntryFormat) ([]noteBlock, Diagnostics) {
+	malformed := func(offset int, format string, args ...interface{}) Diagnostics {
+		line, col := position(entry.Body, offset)
+		return Diagnostics{{
+			Code:     EntryErrorMalformedEntry,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf(format, args...),
+			Note:     -1,
+			Line:     line,
+			Column:   col,
+		}}
+	}
+
+	decoded, offset, err := decodeStructuredEntry(entry.Body, format)
+	if err != nil {
+		return nil, malformed(offset, "malformed %s changelog entry: %s", strings.ToUpper(string(format)), err)
+	}
+	res := make([]noteBlock, 0, len(decoded.Notes))
+	for i, n := range decoded.Notes {
+		b := noteBlock{start: -1, typeStart: -1, bodyStart: -1}
+		keys := make([]string, 0, len(n.Attributes))
+		for k := range n.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			switch v := n.Attributes[k].(type) {
+			case string, bool, int, int64, float64:
+				b.attributes = append(b.attributes, headerAttribute{text: k, key: k, value: fmt.Sprint(v), offset: -1})
+			default:
+				return nil, malformed(-1, "malformed %s changelog entry: attribute %q of note %d must be a string, number or boolean", strings.ToUpper(string(format)), k, i+1)
+			}
+		}
+		b.setNote(entry, strings.TrimSpace(n.Type), strings.TrimSpace(n.Body))
+		res = append(res, b)
+	}
+	return res, nil
+}
@@ -0,0 +1,219 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// fileHistory holds the commits that first and last changed a file within a
+// range of history.
+type fileHistory struct {
+	first, last *object.Commit
+}
+
+// historyRange is a range of history, from a commit (exclusive) to the
+// commit the history is walked from, and the entries in a directory whose
+// changes within it are looked for.
+type historyRange struct {
+	// from is the start of the range, or nil for the whole history.
+	from *plumbing.Hash
+
+	dir   string
+	names map[string]bool
+}
+
+// rangesHistory walks the history of to and, for each of ranges, skipping
+// the commits reachable from its start, attributes each of its names in its
+// dir to the first and last commits that changed it. A commit changes a file
+// if the file differs from its version in every parent of the commit, so
+// changes brought in by merges are attributed to the commits that made them.
+//
+// The history is walked once for all ranges, in committer time order,
+// visiting the commits in any of them. The histories are returned in the
+// order of ranges.
+//
+// In shallow repositories, the commits at the shallow boundary are treated
+// as root commits.
+func rangesHistory(ctx context.Context, r *git.Repository, to plumbing.Hash, ranges []historyRange) ([]map[string]*fileHistory, error) {
+	res := make([]map[string]*fileHistory, len(ranges))
+	var active []int
+	for i, rg := range ranges {
+		res[i] = make(map[string]*fileHistory, len(rg.names))
+		if len(rg.names) > 0 {
+			active = append(active, i)
+		}
+	}
+	if len(active) == 0 {
+		return res, nil
+	}
+
+	missing, err := shallowParents(r)
+	if err != nil {
+		return nil, err
+	}
+	// excluded holds, for each range, the commits reachable from its start,
+	// and seen the commits excluded from every range, which aren't walked
+	excluded := make([]map[plumbing.Hash]bool, len(ranges))
+	var seen map[plumbing.Hash]bool
+	for _, i := range active {
+		excluded[i] = map[plumbing.Hash]bool{}
+		for h := range missing {
+			excluded[i][h] = true
+		}
+		if from := ranges[i].from; from != nil {
+			start, err := r.CommitObject(*from)
+			if err != nil {
+				return nil, err
+			}
+			err = object.NewCommitPreorderIter(start, missing, nil).ForEach(func(c *object.Commit) error {
+				excluded[i][c.Hash] = true
+				return ctx.Err()
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		if seen == nil {
+			seen = make(map[plumbing.Hash]bool, len(excluded[i]))
+			for h := range excluded[i] {
+				seen[h] = true
+			}
+			continue
+		}
+		for h := range seen {
+			if !excluded[i][h] {
+				delete(seen, h)
+			}
+		}
+	}
+
+	start, err := r.CommitObject(to)
+	if err != nil {
+		return nil, err
+	}
+	err = object.NewCommitIterCTime(start, seen, nil).ForEach(func(c *object.Commit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var parents []*object.Commit
+		for _, h := range c.ParentHashes {
+			if missing[h] {
+				continue
+			}
+			parent, err := r.CommitObject(h)
+			if err != nil {
+				return err
+			}
+			parents = append(parents, parent)
+		}
+		for _, i := range active {
+			if excluded[i][c.Hash] {
+				continue
+			}
+			if err := attributeChanges(c, parents, ranges[i], res[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// attributeChanges attributes the entries of rg that c changed relative to
+// its parents to c, in res. Commits must be attributed newest first.
+func attributeChanges(c *object.Commit, parents []*object.Commit, rg historyRange, res map[string]*fileHistory) error {
+	tree, err := historyDirTree(c, rg.dir)
+	if err != nil {
+		return err
+	}
+	var parentTrees []*object.Tree
+	for _, parent := range parents {
+		pt, err := historyDirTree(parent, rg.dir)
+		if err != nil {
+			return err
+		}
+		if pt.Hash == tree.Hash {
+			// nothing in dir changed relative to this parent, so
+			// nothing can be attributed to this commit
+			return nil
+		}
+		parentTrees = append(parentTrees, pt)
+	}
+	if len(parentTrees) == 0 {
+		// a root commit changes every file it contains, as does a
+		// commit at the boundary of a shallow clone
+		parentTrees = append(parentTrees, &object.Tree{})
+	}
+	for name := range rg.names {
+		hash := entryHash(tree, name)
+		changed := true
+		for _, pt := range parentTrees {
+			if entryHash(pt, name) == hash {
+				changed = false
+				break
+			}
+		}
+		if !changed {
+			continue
+		}
+		// commits are visited newest first, so the first commit seen
+		// is the last to change the file
+		h, ok := res[name]
+		if !ok {
+			h = &fileHistory{last: c}
+			res[name] = h
+		}
+		h.first = c
+	}
+	return nil
+}
+
+// shallowParents returns the set of parents of the commits at the boundary of
+// a shallow repository, which are missing from it.
+func shallowParents(r *git.Repository) (map[plumbing.Hash]bool, error) {
+	shallow, err := r.Storer.Shallow()
+	if err != nil {
+		return nil, err
+	}
+	res := map[plumbing.Hash]bool{}
+	for _, h := range shallow {
+		c, err := r.CommitObject(h)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range c.ParentHashes {
+			res[p] = true
+		}
+	}
+	return res, nil
+}
+
+// historyDirTree returns the tree of dir in c. If dir doesn't exist in c, an
+// empty tree is returned.
+func historyDirTree(c *object.Commit, dir string) (*object.Tree, error) {
+	tree, err := commitDirTree(c, dir)
+	if errors.Is(err, object.ErrDirectoryNotFound) {
+		return &object.Tree{}, nil
+	}
+	return tree, err
+}
+
+// entryHash returns the hash of the file name in tree, or the zero hash if
+// it doesn't exist.
+func entryHash(tree *object.Tree, name string) plumbing.Hash {
+	e, err := tree.FindEntry(name)
+	if err != nil || !e.Mode.IsFile() {
+		return plumbing.ZeroHash
+	}
+	return e.Hash
+}
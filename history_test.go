@@ -0,0 +1,291 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// historyRepo is an in-memory repository whose commits are made a minute
+// apart, and named for the tests.
+type historyRepo struct {
+	t       *testing.T
+	r       *git.Repository
+	wt      *git.Worktree
+	when    time.Time
+	commits map[string]plumbing.Hash
+}
+
+func newHistoryRepo(t *testing.T) *historyRepo {
+	t.Helper()
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &historyRepo{
+		t:       t,
+		r:       r,
+		wt:      wt,
+		when:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		commits: map[string]plumbing.Hash{},
+	}
+}
+
+// commit commits files, by path, on top of the commit named parents[0], or
+// HEAD, and names the commit name. Any other parents make it a merge.
+func (h *historyRepo) commit(name string, files map[string]string, parents ...string) {
+	h.t.Helper()
+	var hashes []plumbing.Hash
+	for _, p := range parents {
+		hashes = append(hashes, h.commits[p])
+	}
+	if len(hashes) > 0 {
+		if err := h.wt.Checkout(&git.CheckoutOptions{Hash: hashes[0], Force: true}); err != nil {
+			h.t.Fatal(err)
+		}
+	}
+	for path, content := range files {
+		if err := util.WriteFile(h.wt.Filesystem, path, []byte(content), 0644); err != nil {
+			h.t.Fatal(err)
+		}
+		if _, err := h.wt.Add(path); err != nil {
+			h.t.Fatal(err)
+		}
+	}
+	h.when = h.when.Add(time.Minute)
+	sig := &object.Signature{Name: "a", Email: "a@example.com", When: h.when}
+	hash, err := h.wt.Commit(name, &git.CommitOptions{
+		Author:            sig,
+		Committer:         sig,
+		Parents:           hashes,
+		AllowEmptyCommits: true,
+	})
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	h.commits[name] = hash
+}
+
+// name returns the name of the commit c.
+func (h *historyRepo) name(c *object.Commit) string {
+	for name, hash := range h.commits {
+		if hash == c.Hash {
+			return name
+		}
+	}
+	return c.Hash.String()
+}
+
+func TestRangesHistory(t *testing.T) {
+	h := newHistoryRepo(t)
+	h.commit("c0", map[string]string{"README.md": "readme"})
+	h.commit("c1", map[string]string{".changelog/1.txt": "v1"})
+	h.commit("c2", map[string]string{".changelog/1.txt": "v2"})
+	h.commit("c3", map[string]string{".changelog/2.txt": "v1"}, "c1")
+	h.commit("c4", map[string]string{".changelog/3.txt": "v1"}, "c2")
+	h.commit("m5", map[string]string{".changelog/2.txt": "v1"}, "c4", "c3")
+	h.commit("c6", map[string]string{".changelog/1.txt": "v3", "README.md": "changed"})
+
+	// firstLast holds the names of the first and last commits changing a
+	// file.
+	type firstLast [2]string
+	cases := []struct {
+		name    string
+		to      string
+		from    string
+		shallow []string
+		want    map[string]firstLast
+	}{
+		{
+			name: "whole history",
+			to:   "c6",
+			want: map[string]firstLast{
+				"1.txt": {"c1", "c6"},
+				"2.txt": {"c3", "c3"},
+				"3.txt": {"c4", "c4"},
+			},
+		},
+		{
+			name: "modified after creation",
+			to:   "c2",
+			want: map[string]firstLast{
+				"1.txt": {"c1", "c2"},
+			},
+		},
+		{
+			name: "since a release",
+			to:   "c6",
+			from: "c2",
+			want: map[string]firstLast{
+				"1.txt": {"c6", "c6"},
+				"2.txt": {"c3", "c3"},
+				"3.txt": {"c4", "c4"},
+			},
+		},
+		{
+			name: "merge",
+			to:   "m5",
+			from: "c4",
+			want: map[string]firstLast{
+				"2.txt": {"c3", "c3"},
+			},
+		},
+		{
+			name:    "shallow boundary",
+			to:      "c6",
+			shallow: []string{"c2", "c3"},
+			want: map[string]firstLast{
+				"1.txt": {"c2", "c6"},
+				"2.txt": {"c3", "c3"},
+				"3.txt": {"c4", "c4"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var shallow []plumbing.Hash
+			for _, name := range tc.shallow {
+				shallow = append(shallow, h.commits[name])
+			}
+			if err := h.r.Storer.SetShallow(shallow); err != nil {
+				t.Fatal(err)
+			}
+			defer h.r.Storer.SetShallow(nil)
+
+			rg := historyRange{
+				dir:   ".changelog",
+				names: map[string]bool{"1.txt": true, "2.txt": true, "3.txt": true},
+			}
+			if tc.from != "" {
+				from := h.commits[tc.from]
+				rg.from = &from
+			}
+			res, err := rangesHistory(context.Background(), h.r, h.commits[tc.to], []historyRange{rg})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]firstLast{}
+			for name, fh := range res[0] {
+				got[name] = firstLast{h.name(fh.first), h.name(fh.last)}
+			}
+			if len(got) != len(tc.want) {
+				t.Errorf("got the history of %v, want %v", got, tc.want)
+			}
+			for name, want := range tc.want {
+				if got[name] != want {
+					t.Errorf("%s: got first and last commits %v, want %v", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRangesHistoryMultipleRanges(t *testing.T) {
+	h := newHistoryRepo(t)
+	h.commit("c1", map[string]string{"a/1.txt": "v1", "b/1.txt": "v1"})
+	h.commit("c2", map[string]string{"a/2.txt": "v1"})
+	h.commit("c3", map[string]string{"b/1.txt": "v2"})
+
+	c1 := h.commits["c1"]
+	res, err := rangesHistory(context.Background(), h.r, h.commits["c3"], []historyRange{
+		{dir: "a", names: map[string]bool{"1.txt": true, "2.txt": true}},
+		{from: &c1, dir: "b", names: map[string]bool{"1.txt": true}},
+		{dir: "c"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 3 {
+		t.Fatalf("got %d histories, want 3", len(res))
+	}
+	for _, tc := range []struct {
+		rg          int
+		name        string
+		first, last string
+	}{
+		{0, "1.txt", "c1", "c1"},
+		{0, "2.txt", "c2", "c2"},
+		{1, "1.txt", "c3", "c3"},
+	} {
+		fh, ok := res[tc.rg][tc.name]
+		if !ok {
+			t.Errorf("range %d: no history for %s", tc.rg, tc.name)
+			continue
+		}
+		if first, last := h.name(fh.first), h.name(fh.last); first != tc.first || last != tc.last {
+			t.Errorf("range %d: %s: got first and last commits %s and %s, want %s and %s", tc.rg, tc.name, first, last, tc.first, tc.last)
+		}
+	}
+	if len(res[2]) != 0 {
+		t.Errorf("range 2: got %d histories for no names", len(res[2]))
+	}
+}
+
+func TestAttributeChanges(t *testing.T) {
+	h := newHistoryRepo(t)
+	h.commit("c1", map[string]string{".changelog/1.txt": "v1", ".changelog/2.txt": "v1"})
+	h.commit("c2", map[string]string{"README.md": "readme"})
+	h.commit("c3", map[string]string{".changelog/2.txt": "v2"}, "c1")
+	h.commit("m4", map[string]string{".changelog/2.txt": "v3"}, "c2", "c3")
+
+	cases := []struct {
+		name    string
+		commit  string
+		parents []string
+		want    []string
+	}{
+		{name: "root commit", commit: "c1", want: []string{"1.txt", "2.txt"}},
+		{name: "directory unchanged", commit: "c2", parents: []string{"c1"}},
+		{name: "file modified", commit: "c3", parents: []string{"c1"}, want: []string{"2.txt"}},
+		{name: "merge changing every parent's version", commit: "m4", parents: []string{"c2", "c3"}, want: []string{"2.txt"}},
+		{name: "merge of a parent's change", commit: "c3", parents: []string{"c1", "c3"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := h.r.CommitObject(h.commits[tc.commit])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var parents []*object.Commit
+			for _, p := range tc.parents {
+				pc, err := h.r.CommitObject(h.commits[p])
+				if err != nil {
+					t.Fatal(err)
+				}
+				parents = append(parents, pc)
+			}
+			rg := historyRange{dir: ".changelog", names: map[string]bool{"1.txt": true, "2.txt": true}}
+			res := map[string]*fileHistory{}
+			if err := attributeChanges(c, parents, rg, res); err != nil {
+				t.Fatal(err)
+			}
+			if len(res) != len(tc.want) {
+				t.Errorf("got %d changed files, want %v", len(res), tc.want)
+			}
+			for _, name := range tc.want {
+				fh, ok := res[name]
+				if !ok {
+					t.Errorf("%s wasn't attributed to %s", name, tc.commit)
+					continue
+				}
+				if fh.first != c || fh.last != c {
+					t.Errorf("%s: got first and last commits %s and %s, want %s", name, h.name(fh.first), h.name(fh.last), tc.commit)
+				}
+			}
+		})
+	}
+}
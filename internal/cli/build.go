@@ -0,0 +1,295 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/hashicorp/go-changelog"
+	"github.com/hashicorp/go-changelog/internal/render"
+)
+
+var buildCommand = &Command{
+	Name:     "build",
+	Args:     "-last-release REF -this-release REF [flags]",
+	Synopsis: "Generate the changelog of a release from its entries",
+	Help: `
+Generates the changelog for a release from the changelog entries added to the
+repository between two git refs, and writes it to stdout or to a changelog
+file. -repo can be the URL of a remote repository, and GITHUB_TOKEN is used to
+authenticate with https URLs. With -last-release auto, the release tags are
+looked up in the same repository, local or remote.`,
+	Setup: setupBuild,
+}
+
+func setupBuild(g *Globals, fset *flag.FlagSet) func(args []string) int {
+	var lastRelease, thisRelease, noteTmpl, changelogTmpl, tagPattern, format, presetName, writePath, version, date string
+	var localFS, amended, prereleases, force, allProjects, writeChangelogs bool
+	var projects stringsFlag
+	fset.StringVar(&lastRelease, "last-release", "", "a git ref to the last commit in the previous release, or \"auto\" to use the closest release tag")
+	fset.StringVar(&thisRelease, "this-release", "", "a git ref to the last commit to include in this release")
+	fset.StringVar(&g.Repo, "git-dir", "", "the directory or URL of the git repo being released; an alias of -repo")
+	fset.StringVar(&noteTmpl, "note-template", "", "the path of the file holding the template to use for each item in the changelog")
+	fset.StringVar(&changelogTmpl, "changelog-template", "", "the path of the file holding the template to use for the entire changelog")
+	fset.StringVar(&presetName, "preset", "", fmt.Sprintf("the built-in templates to use when no template paths are given, one of %v (defaults to %q)", render.PresetNames(), render.DefaultPreset))
+	fset.BoolVar(&localFS, "local-fs", false, "use local filesystem for git operations (may be faster on large repos)")
+	fset.StringVar(&tagPattern, "tag-pattern", "", "the glob release tags match when -last-release is \"auto\" (defaults to \"v*\")")
+	fset.BoolVar(&prereleases, "include-prereleases", false, "allow prerelease tags to be chosen when -last-release is \"auto\"")
+	fset.BoolVar(&amended, "amended", false, "include notes from entries that were modified since the last release as AmendedNotes")
+	fset.StringVar(&format, "format", "text", "the output format: \"text\" renders the templates, \"json\" outputs the release as JSON")
+	fset.StringVar(&writePath, "write", "", "the path of a changelog file, such as CHANGELOG.md, to update with the section for -version instead of writing to stdout")
+	fset.StringVar(&version, "version", "", "the version being released")
+	fset.StringVar(&date, "date", "", "the date of the release, as YYYY-MM-DD (the release is marked as unreleased if not set)")
+	fset.BoolVar(&force, "force", false, "overwrite the section for -version in the -write file even if it was edited by hand")
+	fset.Var(&projects, "project", "build the changelog of the named project of the config; may be repeated")
+	fset.BoolVar(&allProjects, "all-projects", false, "build the changelogs of every project of the config")
+	fset.BoolVar(&writeChangelogs, "write-changelogs", false, "update the changelog file of each project built with the section for its version instead of writing to stdout")
+
+	return func(args []string) int {
+		if len(args) > 0 {
+			return usageError(fset, fmt.Sprintf("Unexpected arguments %q.", args))
+		}
+		if format != "text" && format != "json" {
+			return usageError(fset, fmt.Sprintf("Unknown output format %q.", format))
+		}
+		if lastRelease == "" {
+			return usageError(fset, "Must specify last commit in the previous release.")
+		}
+		if thisRelease == "" {
+			return usageError(fset, "Must specify last commit in the release.")
+		}
+
+		cfg, err := g.openConfig()
+		if err != nil {
+			return fail(err)
+		}
+		repoDir := g.Repo
+		if repoDir == "" {
+			repoDir = cfg.Root
+		}
+
+		var releaseDate time.Time
+		if date != "" {
+			releaseDate, err = time.Parse("2006-01-02", date)
+			if err != nil {
+				return usageError(fset, fmt.Sprintf("Error parsing date %q, must be formatted as YYYY-MM-DD.", date))
+			}
+		}
+
+		if allProjects {
+			projects = append(projects, cfg.ProjectNames()...)
+		}
+		if len(projects) > 0 || writeChangelogs || allProjects {
+			if len(projects) == 0 {
+				return usageError(fset, "Must specify the projects to build with -project or -all-projects, and declare them in the config.")
+			}
+			if g.EntriesDir != "" || tagPattern != "" || writePath != "" {
+				return usageError(fset, "Can't set -entries-dir, -tag-pattern or -write when building projects, they are set for each project in the config.")
+			}
+			if writeChangelogs && format != "text" {
+				return usageError(fset, "Can only write to changelog files with the text format.")
+			}
+			err := buildProjects(g, cfg, projects, projectOptions{
+				lastRelease:   lastRelease,
+				thisRelease:   thisRelease,
+				repoDir:       repoDir,
+				presetName:    presetName,
+				noteTmpl:      noteTmpl,
+				changelogTmpl: changelogTmpl,
+				format:        format,
+				version:       version,
+				date:          releaseDate,
+				localFS:       localFS,
+				amended:       amended,
+				prereleases:   prereleases,
+				write:         writeChangelogs,
+				force:         force,
+			})
+			if err != nil {
+				return fail(err)
+			}
+			return ExitOK
+		}
+
+		entriesDir := g.EntriesDir
+		if entriesDir == "" {
+			entriesDir = cfg.EntriesDir
+		}
+		if noteTmpl == "" {
+			noteTmpl = cfg.ResolvePath(cfg.Templates.ReleaseNote)
+		}
+		if changelogTmpl == "" {
+			changelogTmpl = cfg.ResolvePath(cfg.Templates.Changelog)
+		}
+		if entriesDir == "" {
+			return usageError(fset, "Must specify directory of the changelog entries within the repository being released.")
+		}
+		if writePath != "" && format != "text" {
+			return usageError(fset, "Can only write to a changelog file with the text format.")
+		}
+		if writePath != "" && version == "" {
+			return usageError(fset, "Must specify the version being released to write to a changelog file.")
+		}
+
+		if presetName == "" {
+			presetName = cfg.Templates.Preset
+		}
+		var renderer *render.Renderer
+		if format == "text" {
+			templates, err := render.LoadTemplates(presetName, changelogTmpl, noteTmpl)
+			if err != nil {
+				return usageError(fset, err.Error())
+			}
+			renderer, err = render.New(templates, cfg)
+			if err != nil {
+				return fail(err)
+			}
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		r, err := openRepository(ctx, repoDir, localFS)
+		if err != nil {
+			return fail(err)
+		}
+
+		if lastRelease == "auto" {
+			if tagPattern == "" {
+				tagPattern = cfg.TagPattern
+			}
+			lastRelease, err = changelog.PreviousRelease(r, thisRelease, changelog.ReleaseTagOptions{
+				Pattern:            tagPattern,
+				IncludePrereleases: prereleases,
+			})
+			if errors.Is(err, changelog.ErrNoPreviousRelease) {
+				g.Infof("No release found before %s, including all entries.\n", thisRelease)
+				lastRelease = "-"
+			} else if err != nil {
+				return fail(err)
+			} else {
+				g.Infof("Using %s as the last release.\n", lastRelease)
+			}
+		}
+
+		g.Debugf("Diffing the entries in %s of %s from %s to %s.\n", entriesDir, repoDir, lastRelease, thisRelease)
+		ranges := []changelog.DiffRange{{Since: lastRelease, Dir: entriesDir, Naming: entryNaming(cfg)}}
+		diffed, err := changelog.DiffRepositoryRanges(ctx, r, thisRelease, ranges)
+		if err != nil {
+			return fail(err)
+		}
+		entries := diffed[0]
+
+		for i := 0; i < entries.Len(); i++ {
+			entry := entries.Get(i)
+			if entry.Status == changelog.EntryRemoved {
+				fmt.Fprintf(os.Stderr, "Warning: changelog entry %s was removed in %s\n", changelog.TrimEntryExtension(entry.Issue), entry.Hash)
+			}
+		}
+		release := changelog.NewReleaseWith(entries, cfg.NoteParser(), amended)
+		release.Version = version
+		release.Date = releaseDate
+
+		if format == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(release); err != nil {
+				return fail(fmt.Errorf("error encoding release: %w", err))
+			}
+			return ExitOK
+		}
+
+		out, err := renderer.Render(release)
+		if err != nil {
+			return fail(err)
+		}
+		if writePath == "" {
+			os.Stdout.Write(out)
+			return ExitOK
+		}
+		if err := writeSection(writePath, version, releaseDate, out, force); err != nil {
+			return fail(err)
+		}
+		g.Infof("Updated the %s section of %s\n", version, writePath)
+		return ExitOK
+	}
+}
+
+// openRepository opens the git repository at repoDir with localFS, or
+// clones it into memory otherwise, authenticating with GITHUB_TOKEN for
+// https URLs.
+func openRepository(ctx context.Context, repoDir string, localFS bool) (*git.Repository, error) {
+	if localFS {
+		if isRemote(repoDir) {
+			return nil, fmt.Errorf("can't use -local-fs with the remote repository %s", repoDir)
+		}
+		r, err := git.PlainOpen(repoDir)
+		if err != nil {
+			return nil, fmt.Errorf("error opening repository at %q: %w", repoDir, err)
+		}
+		return r, nil
+	}
+	var opts changelog.DiffOptions
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" && strings.HasPrefix(repoDir, "https://") {
+		// GitHub accepts any non-empty username alongside a token
+		opts.Auth = &http.BasicAuth{Username: "x-access-token", Password: token}
+	}
+	r, err := changelog.CloneRepository(ctx, repoDir, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error cloning repository %s: %w", repoDir, err)
+	}
+	return r, nil
+}
+
+// entryNaming returns the naming scheme entries are diffed with: the one of
+// cfg if it sets an entry pattern, or nil to diff every entry file.
+func entryNaming(cfg *changelog.Config) *changelog.EntryNaming {
+	if cfg.EntryPattern == "" {
+		return nil
+	}
+	return cfg.EntryNaming()
+}
+
+// scpURLRE matches the scp-like URLs of remote repositories, such as
+// git@github.com:org/repo.git.
+var scpURLRE = regexp.MustCompile(`^[\w.-]+@[\w.-]+:`)
+
+// isRemote reports whether repo is the URL of a remote repository rather
+// than a directory.
+func isRemote(repo string) bool {
+	return strings.Contains(repo, "://") || scpURLRE.MatchString(repo)
+}
+
+// writeSection replaces or inserts the section for version in the changelog
+// file at path with body. Sections edited by hand since they were generated
+// are only replaced if force is true.
+func writeSection(path, version string, date time.Time, body []byte, force bool) error {
+	src, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+	doc := changelog.ParseDocument(src)
+	if s := doc.Section(version); s != nil && s.Edited() && !force {
+		if s.Checksum == "" {
+			return fmt.Errorf("the %s section of %s (line %d) wasn't generated by changelog-build; use -force to overwrite it", s.Version, path, s.Line)
+		}
+		return fmt.Errorf("the %s section of %s (line %d) was edited by hand since it was generated; use -force to overwrite it", s.Version, path, s.Line)
+	}
+	title := "(Unreleased)"
+	if !date.IsZero() {
+		title = date.Format("(January 2, 2006)")
+	}
+	return os.WriteFile(path, doc.SetSection(version, title, body), 0644)
+}
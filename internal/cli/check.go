@@ -0,0 +1,326 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/hashicorp/go-changelog"
+)
+
+var checkCommand = &Command{
+	Name:     "check",
+	Args:     "[flags] [FILE | DIR | GLOB]...",
+	Synopsis: "Check changelog entry files for problems",
+	Help: `
+Checks the given changelog entry files, the entry files in the given
+directories, the files matching the given globs, or stdin.`,
+	Setup: setupCheck,
+}
+
+func setupCheck(g *Globals, fset *flag.FlagSet) func(args []string) int {
+	var rules, format, since string
+	var parallelism int
+	fset.StringVar(&rules, "rules", "", fmt.Sprintf("a comma-separated list of NAME[=SEVERITY] lint rules to check in addition to the configured ones, where NAME is one of %v and SEVERITY is error (the default), warning or off", changelog.RuleNames()))
+	fset.StringVar(&format, "format", "text", fmt.Sprintf("the output format of the problems found, one of %v", formats))
+	fset.StringVar(&since, "since", "", "a git ref; check the entries added or modified in HEAD since its merge base with this ref instead of the given files")
+	fset.IntVar(&parallelism, "parallelism", runtime.NumCPU(), "the number of entries checked concurrently")
+
+	return func(args []string) int {
+		if !validFormat(format) {
+			return usageError(fset, fmt.Sprintf("Unknown output format %q.", format))
+		}
+		if since != "" && len(args) > 0 {
+			return usageError(fset, "Can't check files when -since is set.")
+		}
+		if parallelism < 1 {
+			parallelism = 1
+		}
+
+		cfg, err := g.openConfig()
+		if err != nil {
+			return fail(err)
+		}
+		linter, err := changelog.NewLinter(ruleConfigs(cfg.Rules, rules))
+		if err != nil {
+			return usageError(fset, err.Error())
+		}
+		linter.Notes = cfg.NoteParser()
+		entriesDir := g.EntriesDir
+		if entriesDir == "" {
+			entriesDir = cfg.EntriesDir
+		}
+		repoDir := g.Repo
+		if repoDir == "" {
+			repoDir = cfg.Root
+		}
+
+		var inputs []input
+		// a summary is printed unless a single file, or stdin, is checked
+		summary := true
+		switch {
+		case since != "":
+			inputs, err = sinceInputs(repoDir, since, entriesDir)
+		case len(args) == 0:
+			inputs, err = stdinInput()
+			summary = false
+		default:
+			inputs, summary, err = pathInputs(cfg.Root, cfg.ResolvePath(entriesDir), args)
+		}
+		if err != nil {
+			return fail(err)
+		}
+		g.Debugf("Checking %d entries.\n", len(inputs))
+
+		files := make([]fileDiagnostics, len(inputs))
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, parallelism)
+		for i, in := range inputs {
+			wg.Add(1)
+			go func(i int, in input) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				entry := changelog.Entry{
+					Body:   in.body,
+					Format: changelog.EntryFormatOf(in.path),
+				}
+				diags := cfg.NoteParser().Diagnose(&entry)
+				if len(diags.WithCode(changelog.EntryErrorNotFound)) == 0 {
+					diags = append(diags, linter.Lint(&entry)...)
+					diags.Sort()
+				}
+				if _, _, ok := cfg.EntryNaming().Match(in.name); cfg.EntryPattern != "" && in.name != "" && !ok {
+					diags = append(diags, changelog.Diagnostic{
+						Code:     changelog.EntryErrorInvalidName,
+						Severity: changelog.SeverityError,
+						Message:  fmt.Sprintf("entry file %s doesn't follow the naming scheme %s of the entries directory", in.name, cfg.EntryNaming()),
+						Note:     -1,
+					})
+				}
+				files[i] = fileDiagnostics{path: in.path, diags: diags}
+			}(i, in)
+		}
+		wg.Wait()
+
+		if err := writeDiagnostics(os.Stdout, format, files); err != nil {
+			return fail(err)
+		}
+		if summary && !g.Quiet {
+			writeSummary(os.Stderr, files)
+		}
+		for _, f := range files {
+			if f.diags.HasErrors() {
+				return ExitFailure
+			}
+		}
+		return ExitOK
+	}
+}
+
+// input is the contents of an entry to check.
+type input struct {
+	// path is the path of the entry file as it is reported.
+	path string
+
+	// name is the slash-separated path of the entry file relative to the
+	// entries directory, or empty if it is outside of it.
+	name string
+	body string
+}
+
+// stdinInput reads the entry to check from stdin.
+func stdinInput() ([]input, error) {
+	b, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, errors.New("error reading from stdin")
+	}
+	return []input{{path: displayPath("", ""), body: string(b)}}, nil
+}
+
+// pathInputs reads the entries at paths, which may be files, directories,
+// whose entry files are read recursively, or globs. It also reports whether
+// more than one file may have been given.
+func pathInputs(root, entriesDir string, paths []string) ([]input, bool, error) {
+	var files []string
+	multi := len(paths) > 1
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && info.IsDir() {
+			err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() && changelog.IsEntryFile(path) && !changelog.IsConfigFile(path) {
+					files = append(files, path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, false, err
+			}
+			multi = true
+			continue
+		}
+		if strings.ContainsAny(p, "*?[") {
+			matches, err := filepath.Glob(p)
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid glob %q: %w", p, err)
+			}
+			if len(matches) == 0 {
+				return nil, false, fmt.Errorf("no files match %s", p)
+			}
+			files = append(files, matches...)
+			multi = true
+			continue
+		}
+		files = append(files, p)
+	}
+
+	res := make([]input, 0, len(files))
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, false, fmt.Errorf("error reading from %s", f)
+		}
+		res = append(res, input{path: displayPath(root, f), name: relativeName(entriesDir, f), body: string(b)})
+	}
+	return res, multi, nil
+}
+
+// sinceInputs returns the entry files in dir that were added or modified in
+// HEAD since its merge base with the ref since, in the git repository at
+// root or one of its parents.
+func sinceInputs(root, since, dir string) ([]input, error) {
+	r, err := git.PlainOpenWithOptions(root, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("error opening repository at %q: %w", root, err)
+	}
+	base, err := mergeBase(r, since, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := changelog.DiffLocalChanges(wt.Filesystem.Root(), base, "HEAD", dir)
+	if err != nil {
+		return nil, err
+	}
+	var res []input
+	for i := 0; i < entries.Len(); i++ {
+		e := entries.Get(i)
+		if e.Status == changelog.EntryRemoved || !changelog.IsEntryFile(e.Issue) {
+			continue
+		}
+		res = append(res, input{path: filepath.ToSlash(filepath.Join(dir, e.Issue)), name: e.Issue, body: e.Body})
+	}
+	return res, nil
+}
+
+// mergeBase returns the hash of the best common ancestor of the refs a and
+// b.
+func mergeBase(r *git.Repository, a, b string) (string, error) {
+	var commits []*object.Commit
+	for _, ref := range []string{a, b} {
+		hash, err := r.ResolveRevision(plumbing.Revision(ref))
+		if err != nil {
+			return "", fmt.Errorf("could not resolve revision %s: %w", ref, err)
+		}
+		c, err := r.CommitObject(*hash)
+		if err != nil {
+			return "", err
+		}
+		commits = append(commits, c)
+	}
+	bases, err := commits[0].MergeBase(commits[1])
+	if err != nil {
+		return "", err
+	}
+	if len(bases) == 0 {
+		return "", fmt.Errorf("%s and %s have no common ancestor", a, b)
+	}
+	return bases[0].Hash.String(), nil
+}
+
+// validFormat reports whether format is one of formats.
+func validFormat(format string) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// relativeName returns the slash-separated path of the file at path relative
+// to dir, or an empty string if it is outside of dir.
+func relativeName(dir, path string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(absDir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// displayPath returns the path of an entry file as it is reported: relative
+// to root, the root of the project, with forward slashes, if it is inside
+// root, so the output is the same from any directory and annotations line
+// up with the files of the repository. An empty path is "<stdin>".
+func displayPath(root, path string) string {
+	if path == "" {
+		return "<stdin>"
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// ruleConfigs returns the configured rules with those in flag, a
+// comma-separated list of NAME[=SEVERITY], added or overriding their
+// severity.
+func ruleConfigs(configured []*changelog.RuleConfig, flag string) []*changelog.RuleConfig {
+	res := append([]*changelog.RuleConfig{}, configured...)
+	for _, r := range strings.Split(flag, ",") {
+		name, severity, _ := strings.Cut(strings.TrimSpace(r), "=")
+		if name == "" {
+			continue
+		}
+		c := &changelog.RuleConfig{Name: name}
+		for _, configured := range configured {
+			if configured.Name == name {
+				*c = *configured
+			}
+		}
+		c.Severity = severity
+		res = append(res, c)
+	}
+	return res
+}
@@ -0,0 +1,102 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-changelog"
+)
+
+func TestRuleConfigs(t *testing.T) {
+	configured := []*changelog.RuleConfig{
+		{Name: "max-length", Severity: "warning", Max: 80},
+		{Name: "capitalized"},
+	}
+	cases := []struct {
+		name string
+		flag string
+		want []changelog.RuleConfig
+	}{
+		{
+			name: "no flag",
+			want: []changelog.RuleConfig{
+				{Name: "max-length", Severity: "warning", Max: 80},
+				{Name: "capitalized"},
+			},
+		},
+		{
+			name: "added rules",
+			flag: "no-trailing-period, subcategory-prefix=warning",
+			want: []changelog.RuleConfig{
+				{Name: "max-length", Severity: "warning", Max: 80},
+				{Name: "capitalized"},
+				{Name: "no-trailing-period"},
+				{Name: "subcategory-prefix", Severity: "warning"},
+			},
+		},
+		{
+			name: "overridden severity",
+			flag: "max-length=error,capitalized=off",
+			want: []changelog.RuleConfig{
+				{Name: "max-length", Severity: "warning", Max: 80},
+				{Name: "capitalized"},
+				{Name: "max-length", Severity: "error", Max: 80},
+				{Name: "capitalized", Severity: "off"},
+			},
+		},
+		{
+			name: "configured rule without a severity",
+			flag: "max-length",
+			want: []changelog.RuleConfig{
+				{Name: "max-length", Severity: "warning", Max: 80},
+				{Name: "capitalized"},
+				{Name: "max-length", Max: 80},
+			},
+		},
+		{
+			name: "empty names",
+			flag: ",,capitalized=warning,",
+			want: []changelog.RuleConfig{
+				{Name: "max-length", Severity: "warning", Max: 80},
+				{Name: "capitalized"},
+				{Name: "capitalized", Severity: "warning"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []changelog.RuleConfig
+			for _, c := range ruleConfigs(configured, tc.flag) {
+				got = append(got, *c)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ruleConfigs(%q) = %+v, want %+v", tc.flag, got, tc.want)
+			}
+			if configured[0].Severity != "warning" || configured[1].Severity != "" {
+				t.Errorf("ruleConfigs(%q) modified the configured rules", tc.flag)
+			}
+		})
+	}
+}
+
+func TestRuleConfigsLinter(t *testing.T) {
+	configured := []*changelog.RuleConfig{{Name: "capitalized", Severity: "warning"}}
+	l, err := changelog.NewLinter(ruleConfigs(configured, "capitalized=off,no-trailing-period=warning"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range l.Lint(&changelog.Entry{Body: "```release-note:bug\nfixed a crash.\n```\n"}) {
+		got = append(got, d.String())
+	}
+	want := []string{"2:1: warning: note must not end with a period (no-trailing-period)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if _, err := changelog.NewLinter(ruleConfigs(nil, "capitalized=fatal")); err == nil {
+		t.Error("NewLinter accepted the severity fatal")
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hashicorp/go-changelog"
+)
+
+// formats lists the output formats of the diagnostics.
+var formats = []string{"text", "json", "sarif", "github"}
+
+// fileDiagnostics holds the diagnostics reported for one entry file.
+type fileDiagnostics struct {
+	// path is the path of the file, relative to the repository root when
+	// it is inside it, or "<stdin>".
+	path  string
+	diags changelog.Diagnostics
+}
+
+// writeDiagnostics writes the diagnostics of files to w in format. Text
+// diagnostics are written to stderr instead, alongside the summary.
+func writeDiagnostics(w io.Writer, format string, files []fileDiagnostics) error {
+	switch format {
+	case "text":
+		writeText(os.Stderr, files)
+		return nil
+	case "json":
+		return writeJSON(w, files)
+	case "sarif":
+		return writeSARIF(w, files)
+	case "github":
+		return writeGitHub(w, files)
+	default:
+		return fmt.Errorf("unknown output format %q, must be one of %v", format, formats)
+	}
+}
+
+// writeText writes the diagnostics as "FILE:LINE:COLUMN: SEVERITY: MESSAGE".
+func writeText(w io.Writer, files []fileDiagnostics) {
+	for _, f := range files {
+		for _, d := range f.diags {
+			if d.Line == 0 {
+				fmt.Fprintf(w, "%s: %s\n", f.path, d)
+			} else {
+				fmt.Fprintf(w, "%s:%s\n", f.path, d)
+			}
+		}
+	}
+}
+
+type diagnosticJSON struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Rule     string `json:"rule,omitempty"`
+	Note     int    `json:"note"`
+	Message  string `json:"message"`
+}
+
+// writeJSON writes the diagnostics as a JSON array.
+func writeJSON(w io.Writer, files []fileDiagnostics) error {
+	res := []diagnosticJSON{}
+	for _, f := range files {
+		for _, d := range f.diags {
+			res = append(res, diagnosticJSON{
+				File:     f.path,
+				Line:     d.Line,
+				Column:   d.Column,
+				Severity: string(d.Severity),
+				Code:     string(d.Code),
+				Rule:     d.Rule,
+				Note:     d.Note,
+				Message:  d.Message,
+			})
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(res)
+}
+
+// ruleID returns the identifier of the check that reported d.
+func ruleID(d changelog.Diagnostic) string {
+	if d.Rule != "" {
+		return d.Rule
+	}
+	return string(d.Code)
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+// writeSARIF writes the diagnostics as a SARIF 2.1.0 log.
+func writeSARIF(w io.Writer, files []fileDiagnostics) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "changelog-check",
+			InformationURI: "https://github.com/hashicorp/go-changelog",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	seen := map[string]bool{}
+	for _, f := range files {
+		for _, d := range f.diags {
+			id := ruleID(d)
+			if !seen[id] {
+				seen[id] = true
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id})
+			}
+			res := sarifResult{
+				RuleID:  id,
+				Level:   string(d.Severity),
+				Message: sarifMessage{Text: d.Message},
+			}
+			if f.path != "<stdin>" {
+				loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: f.path},
+				}}
+				if d.Line > 0 {
+					loc.PhysicalLocation.Region = &sarifRegion{StartLine: d.Line, StartColumn: d.Column}
+				}
+				res.Locations = []sarifLocation{loc}
+			}
+			run.Results = append(run.Results, res)
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// writeGitHub writes the diagnostics as GitHub Actions workflow commands,
+// which annotate the offending lines of pull requests.
+func writeGitHub(w io.Writer, files []fileDiagnostics) error {
+	for _, f := range files {
+		for _, d := range f.diags {
+			props := []string{}
+			if f.path != "<stdin>" {
+				props = append(props, "file="+escapeProperty(f.path))
+				if d.Line > 0 {
+					props = append(props, fmt.Sprintf("line=%d", d.Line), fmt.Sprintf("col=%d", d.Column))
+				}
+			}
+			props = append(props, "title="+escapeProperty(ruleID(d)))
+			if _, err := fmt.Fprintf(w, "::%s %s::%s\n", d.Severity, strings.Join(props, ","), escapeData(d.Message)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes the value of a workflow command property.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// writeSummary writes a table of the number of errors and warnings found in
+// each file to w.
+func writeSummary(w io.Writer, files []fileDiagnostics) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tERRORS\tWARNINGS\tRESULT")
+	var failed int
+	for _, f := range files {
+		var errs, warnings int
+		for _, d := range f.diags {
+			if d.Severity == changelog.SeverityError {
+				errs++
+			} else {
+				warnings++
+			}
+		}
+		result := "ok"
+		if errs > 0 {
+			result = "fail"
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", f.path, errs, warnings, result)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "%d of %d entries failed the check\n", failed, len(files))
+}
@@ -0,0 +1,197 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/go-changelog"
+)
+
+// formatFiles are the diagnostics the output formats are tested with.
+var formatFiles = []fileDiagnostics{
+	{
+		path: ".changelog/12.txt",
+		diags: changelog.Diagnostics{
+			{Code: changelog.EntryErrorUnknownTypes, Severity: changelog.SeverityError, Message: `unknown changelog type "bogus"`, Note: 0, Line: 1, Column: 16},
+			{Code: changelog.EntryErrorRule, Severity: changelog.SeverityWarning, Message: "note must not end with a period (no-trailing-period)", Rule: "no-trailing-period", Note: 1, Line: 5, Column: 1},
+		},
+	},
+	{
+		path: ".changelog/notes,v2.md",
+		diags: changelog.Diagnostics{
+			{Code: changelog.EntryErrorInvalidName, Severity: changelog.SeverityError, Message: "entry file notes,v2.md doesn't follow the naming scheme {issue}", Note: -1},
+		},
+	},
+	{
+		path: "<stdin>",
+		diags: changelog.Diagnostics{
+			{Code: changelog.EntryErrorNotFound, Severity: changelog.SeverityError, Message: "no changelog entry found in: 100% done\nnext", Note: -1},
+		},
+	},
+}
+
+func TestWriteDiagnostics(t *testing.T) {
+	cases := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "json",
+			want: `[
+  {
+    "file": ".changelog/12.txt",
+    "line": 1,
+    "column": 16,
+    "severity": "error",
+    "code": "UNKNOWN_TYPES",
+    "note": 0,
+    "message": "unknown changelog type \"bogus\""
+  },
+  {
+    "file": ".changelog/12.txt",
+    "line": 5,
+    "column": 1,
+    "severity": "warning",
+    "code": "RULE",
+    "rule": "no-trailing-period",
+    "note": 1,
+    "message": "note must not end with a period (no-trailing-period)"
+  },
+  {
+    "file": ".changelog/notes,v2.md",
+    "severity": "error",
+    "code": "INVALID_NAME",
+    "note": -1,
+    "message": "entry file notes,v2.md doesn't follow the naming scheme {issue}"
+  },
+  {
+    "file": "<stdin>",
+    "severity": "error",
+    "code": "NOT_FOUND",
+    "note": -1,
+    "message": "no changelog entry found in: 100% done\nnext"
+  }
+]
+`,
+		},
+		{
+			format: "sarif",
+			want: `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "changelog-check",
+          "informationUri": "https://github.com/hashicorp/go-changelog",
+          "rules": [
+            {
+              "id": "UNKNOWN_TYPES"
+            },
+            {
+              "id": "no-trailing-period"
+            },
+            {
+              "id": "INVALID_NAME"
+            },
+            {
+              "id": "NOT_FOUND"
+            }
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "UNKNOWN_TYPES",
+          "level": "error",
+          "message": {
+            "text": "unknown changelog type \"bogus\""
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": ".changelog/12.txt"
+                },
+                "region": {
+                  "startLine": 1,
+                  "startColumn": 16
+                }
+              }
+            }
+          ]
+        },
+        {
+          "ruleId": "no-trailing-period",
+          "level": "warning",
+          "message": {
+            "text": "note must not end with a period (no-trailing-period)"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": ".changelog/12.txt"
+                },
+                "region": {
+                  "startLine": 5,
+                  "startColumn": 1
+                }
+              }
+            }
+          ]
+        },
+        {
+          "ruleId": "INVALID_NAME",
+          "level": "error",
+          "message": {
+            "text": "entry file notes,v2.md doesn't follow the naming scheme {issue}"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": ".changelog/notes,v2.md"
+                }
+              }
+            }
+          ]
+        },
+        {
+          "ruleId": "NOT_FOUND",
+          "level": "error",
+          "message": {
+            "text": "no changelog entry found in: 100% done\nnext"
+          }
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			format: "github",
+			want: `::error file=.changelog/12.txt,line=1,col=16,title=UNKNOWN_TYPES::unknown changelog type "bogus"
+::warning file=.changelog/12.txt,line=5,col=1,title=no-trailing-period::note must not end with a period (no-trailing-period)
+::error file=.changelog/notes%2Cv2.md,title=INVALID_NAME::entry file notes,v2.md doesn't follow the naming scheme {issue}
+::error title=NOT_FOUND::no changelog entry found in: 100%25 done%0Anext
+`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeDiagnostics(&buf, tc.format, formatFiles); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cli implements the changelog command and its subcommands, which
+// are also installed as the changelog-build, changelog-check and other
+// standalone binaries.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-changelog"
+)
+
+// The exit codes of every command.
+const (
+	// ExitOK is returned when the command succeeded, and the checked
+	// entries, pull request or changelog have no problems.
+	ExitOK = 0
+
+	// ExitFailure is returned when problems were found, or the command
+	// failed.
+	ExitFailure = 1
+
+	// ExitUsage is returned when the command was used incorrectly, such as
+	// with an unknown flag or a missing argument.
+	ExitUsage = 2
+)
+
+// Command is a subcommand of the changelog command.
+type Command struct {
+	// Name is the name of the subcommand, and Aliases other names it can be
+	// run with.
+	Name    string
+	Aliases []string
+
+	// Args describes the arguments of the subcommand in its usage line.
+	Args string
+
+	// Synopsis is a one line description of the subcommand, and Help a
+	// longer one.
+	Synopsis string
+	Help     string
+
+	// Setup defines the flags of the subcommand on fs, and returns the
+	// function running it with the remaining arguments.
+	Setup func(g *Globals, fs *flag.FlagSet) func(args []string) int
+}
+
+// Commands are the subcommands of the changelog command.
+var Commands []*Command
+
+func init() {
+	// set in init, as the completion command refers to Commands
+	Commands = []*Command{
+		buildCommand,
+		checkCommand,
+		newCommand,
+		prCheckCommand,
+		verifyCommand,
+		migrateCommand,
+		completionCommand,
+	}
+}
+
+// lookup returns the command named name, or nil.
+func lookup(name string) *Command {
+	for _, c := range Commands {
+		if c.Name == name {
+			return c
+		}
+		for _, a := range c.Aliases {
+			if a == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// Globals holds the flags shared by every command.
+type Globals struct {
+	// Config is the path of the config file.
+	Config string
+
+	// Repo is the directory, or for some commands URL, of the git
+	// repository.
+	Repo string
+
+	// EntriesDir is the directory holding the changelog entries.
+	EntriesDir string
+
+	// Verbose and Quiet raise and lower the amount of information printed
+	// to stderr.
+	Verbose, Quiet bool
+}
+
+// register defines the global flags on fs. Defining flags resets g.
+func (g *Globals) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.Config, "config", "", "the path of the changelog config file (defaults to .changelog/config.hcl or config.yaml, discovered from the current directory)")
+	fs.StringVar(&g.Repo, "repo", "", "the directory of the git repository (defaults to the config root or the current directory)")
+	fs.StringVar(&g.EntriesDir, "entries-dir", "", "the directory holding the changelog entry files (defaults to the configured entries directory)")
+	fs.BoolVar(&g.Verbose, "verbose", false, "print more information about what is being done")
+	fs.BoolVar(&g.Verbose, "v", false, "shorthand for -verbose")
+	fs.BoolVar(&g.Quiet, "quiet", false, "only print errors and problems found")
+	fs.BoolVar(&g.Quiet, "q", false, "shorthand for -quiet")
+}
+
+// globalFlag reports whether name is the name of a global flag.
+func globalFlag(name string) bool {
+	switch name {
+	case "config", "repo", "entries-dir", "verbose", "v", "quiet", "q":
+		return true
+	}
+	return false
+}
+
+// stringsFlag collects the values of a repeated flag.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// openConfig opens the config file set by -config, or discovered from the
+// current directory.
+func (g *Globals) openConfig() (*changelog.Config, error) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := changelog.OpenConfig(g.Config, pwd)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Path != "" {
+		g.Debugf("Using the config file %s.\n", cfg.Path)
+	}
+	return cfg, nil
+}
+
+// Infof prints information to stderr, unless -quiet is set.
+func (g *Globals) Infof(format string, args ...interface{}) {
+	if !g.Quiet {
+		fmt.Fprintf(os.Stderr, format, args...)
+	}
+}
+
+// Debugf prints information to stderr when -verbose is set.
+func (g *Globals) Debugf(format string, args ...interface{}) {
+	if g.Verbose && !g.Quiet {
+		fmt.Fprintf(os.Stderr, format, args...)
+	}
+}
+
+// fail prints err to stderr and returns ExitFailure.
+func fail(err error) int {
+	fmt.Fprintln(os.Stderr, err)
+	return ExitFailure
+}
+
+// usageError prints msg and the usage of fs to stderr, and returns
+// ExitUsage.
+func usageError(fs *flag.FlagSet, msg string) int {
+	fmt.Fprintln(os.Stderr, msg)
+	fmt.Fprintln(os.Stderr, "")
+	fs.SetOutput(os.Stderr)
+	fs.Usage()
+	return ExitUsage
+}
+
+// Main runs the changelog command with args, the arguments following the
+// program name, and returns its exit code.
+func Main(args []string) int {
+	g := &Globals{}
+	fs := flag.NewFlagSet("changelog", flag.ContinueOnError)
+	g.register(fs)
+	fs.Usage = func() { mainUsage(fs.Output(), fs) }
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+
+	if fs.NArg() == 0 {
+		fs.SetOutput(os.Stderr)
+		fs.Usage()
+		return ExitUsage
+	}
+	name, rest := fs.Arg(0), fs.Args()[1:]
+	if name == "help" {
+		return help(rest)
+	}
+	c := lookup(name)
+	if c == nil {
+		return usageError(fs, fmt.Sprintf("Unknown command %q.", name))
+	}
+	return run(g, "changelog "+c.Name, c, rest)
+}
+
+// RunCommand runs the command named name as the standalone binary prog,
+// such as changelog-build, with args, and returns its exit code.
+func RunCommand(prog, name string, args []string) int {
+	c := lookup(name)
+	if c == nil {
+		fmt.Fprintf(os.Stderr, "Unknown command %q.\n", name)
+		return ExitUsage
+	}
+	return run(&Globals{}, prog, c, args)
+}
+
+// run runs c as prog with args.
+func run(g *Globals, prog string, c *Command, args []string) int {
+	fs := flag.NewFlagSet(prog, flag.ContinueOnError)
+	// keep the global flags set before the command
+	parsed := *g
+	runCmd := c.Setup(g, fs)
+	g.register(fs)
+	*g = parsed
+	fs.Usage = func() { commandUsage(fs.Output(), prog, c, fs) }
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	if g.Verbose && g.Quiet {
+		return usageError(fs, "Can't set both -verbose and -quiet.")
+	}
+	return runCmd(fs.Args())
+}
+
+// help prints the usage of the command named in args, or of the changelog
+// command.
+func help(args []string) int {
+	if len(args) == 0 {
+		fs := flag.NewFlagSet("changelog", flag.ContinueOnError)
+		(&Globals{}).register(fs)
+		mainUsage(os.Stdout, fs)
+		return ExitOK
+	}
+	c := lookup(args[0])
+	if c == nil {
+		fmt.Fprintf(os.Stderr, "Unknown command %q.\n", args[0])
+		return ExitUsage
+	}
+	fs := flag.NewFlagSet("changelog "+c.Name, flag.ContinueOnError)
+	g := &Globals{}
+	c.Setup(g, fs)
+	g.register(fs)
+	commandUsage(os.Stdout, "changelog "+c.Name, c, fs)
+	return ExitOK
+}
+
+// mainUsage writes the usage of the changelog command, whose global flags
+// are defined on fs, to w.
+func mainUsage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintln(w, "Usage: changelog [global flags] COMMAND [flags] [ARGS]...")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Generates and checks changelogs from changelog entry files.")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Commands:")
+	width := 0
+	for _, c := range Commands {
+		if len(c.Name) > width {
+			width = len(c.Name)
+		}
+	}
+	for _, c := range Commands {
+		fmt.Fprintf(w, "  %-*s  %s\n", width, c.Name, c.Synopsis)
+	}
+	fmt.Fprintf(w, "  %-*s  %s\n", width, "help", "Show the help of a command")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Global flags, which can also be set after the command:")
+	printFlags(w, fs, globalFlag)
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, `Run "changelog help COMMAND" for the flags of a command.`)
+}
+
+// commandUsage writes the usage of c, run as prog, whose flags are defined
+// on fs, to w.
+func commandUsage(w io.Writer, prog string, c *Command, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: %s %s\n", prog, c.Args)
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, strings.TrimSpace(c.Help))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Flags:")
+	printFlags(w, fs, func(name string) bool { return !globalFlag(name) })
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Global flags:")
+	printFlags(w, fs, globalFlag)
+}
+
+// printFlags writes the defaults of the flags of fs selected by include to
+// w.
+func printFlags(w io.Writer, fs *flag.FlagSet, include func(name string) bool) {
+	sub := flag.NewFlagSet("", flag.ContinueOnError)
+	sub.SetOutput(w)
+	fs.VisitAll(func(f *flag.Flag) {
+		if include(f.Name) {
+			sub.Var(f.Value, f.Name, f.Usage)
+			sub.Lookup(f.Name).DefValue = f.DefValue
+		}
+	})
+	sub.PrintDefaults()
+}
+
+// commandFlags returns the flags of c, including the global flags, sorted
+// by name.
+func commandFlags(c *Command) []*flag.Flag {
+	fs := flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	g := &Globals{}
+	c.Setup(g, fs)
+	g.register(fs)
+	var res []*flag.Flag
+	fs.VisitAll(func(f *flag.Flag) {
+		res = append(res, f)
+	})
+	return res
+}
@@ -0,0 +1,119 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// shells lists the shells completion scripts are generated for.
+var shells = []string{"bash", "zsh", "fish"}
+
+var completionCommand = &Command{
+	Name:     "completion",
+	Args:     "SHELL",
+	Synopsis: "Print the shell completion script of the changelog command",
+	Help: `
+Prints the script completing the commands and flags of the changelog command
+in SHELL, one of bash, zsh or fish. For example, add this line to ~/.bashrc:
+
+  source <(changelog completion bash)`,
+	Setup: setupCompletion,
+}
+
+func setupCompletion(g *Globals, fs *flag.FlagSet) func(args []string) int {
+	return func(args []string) int {
+		if len(args) != 1 {
+			return usageError(fs, fmt.Sprintf("Must specify the shell, one of %v.", shells))
+		}
+		switch args[0] {
+		case "bash":
+			writeBashCompletion(os.Stdout)
+		case "zsh":
+			fmt.Fprintln(os.Stdout, "autoload -U +X bashcompinit && bashcompinit")
+			writeBashCompletion(os.Stdout)
+		case "fish":
+			writeFishCompletion(os.Stdout)
+		default:
+			return usageError(fs, fmt.Sprintf("Unknown shell %q, must be one of %v.", args[0], shells))
+		}
+		return ExitOK
+	}
+}
+
+// commandNames returns the names of the commands, including help.
+func commandNames() []string {
+	res := make([]string, 0, len(Commands)+1)
+	for _, c := range Commands {
+		res = append(res, c.Name)
+	}
+	return append(res, "help")
+}
+
+// flagWords returns the flags of c as they are typed, such as -config.
+func flagWords(c *Command) []string {
+	var res []string
+	for _, f := range commandFlags(c) {
+		res = append(res, "-"+f.Name)
+	}
+	return res
+}
+
+// writeBashCompletion writes a bash completion script to w. Flags are
+// completed after the command, and files otherwise.
+func writeBashCompletion(w io.Writer) {
+	fmt.Fprintln(w, "_changelog() {")
+	fmt.Fprintln(w, `    local cur="${COMP_WORDS[COMP_CWORD]}" cmd="" w`)
+	fmt.Fprintln(w, `    for w in "${COMP_WORDS[@]:1:COMP_CWORD-1}"; do`)
+	fmt.Fprintln(w, `        case "$w" in`)
+	fmt.Fprintf(w, "        %s) cmd=\"$w\"; break ;;\n", strings.Join(commandNames(), "|"))
+	fmt.Fprintln(w, `        esac`)
+	fmt.Fprintln(w, `    done`)
+	fmt.Fprintln(w, `    local words`)
+	fmt.Fprintln(w, `    case "$cmd" in`)
+	fmt.Fprintf(w, "    \"\") words=%q ;;\n", strings.Join(commandNames(), " ")+" "+strings.Join(globalFlagWords(), " "))
+	fmt.Fprintf(w, "    help) words=%q ;;\n", strings.Join(commandNames(), " "))
+	for _, c := range Commands {
+		fmt.Fprintf(w, "    %s) words=%q ;;\n", c.Name, strings.Join(flagWords(c), " "))
+	}
+	fmt.Fprintln(w, `    esac`)
+	fmt.Fprintln(w, `    if [[ -z "$cmd" || "$cmd" == help || "$cur" == -* ]]; then`)
+	fmt.Fprintln(w, `        COMPREPLY=($(compgen -W "$words" -- "$cur"))`)
+	fmt.Fprintln(w, `    fi`)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "complete -o default -F _changelog changelog")
+}
+
+// globalFlagWords returns the global flags as they are typed.
+func globalFlagWords() []string {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	(&Globals{}).register(fs)
+	var res []string
+	fs.VisitAll(func(f *flag.Flag) {
+		res = append(res, "-"+f.Name)
+	})
+	return res
+}
+
+// writeFishCompletion writes a fish completion script to w.
+func writeFishCompletion(w io.Writer) {
+	names := strings.Join(commandNames(), " ")
+	fmt.Fprintf(w, "complete -c changelog -n 'not __fish_seen_subcommand_from %s' -f -a help -d 'Show the help of a command'\n", names)
+	for _, c := range Commands {
+		fmt.Fprintf(w, "complete -c changelog -n 'not __fish_seen_subcommand_from %s' -f -a %s -d %s\n", names, c.Name, fishQuote(c.Synopsis))
+		fmt.Fprintf(w, "complete -c changelog -n '__fish_seen_subcommand_from help' -f -a %s\n", c.Name)
+		for _, f := range commandFlags(c) {
+			fmt.Fprintf(w, "complete -c changelog -n '__fish_seen_subcommand_from %s' -o %s -d %s\n", c.Name, f.Name, fishQuote(f.Usage))
+		}
+	}
+}
+
+// fishQuote quotes s as a fish string.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
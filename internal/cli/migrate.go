@@ -0,0 +1,228 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-changelog"
+	"github.com/hashicorp/go-changelog/parser"
+)
+
+// keepAChangelogHeadings are the headings used by changelogs following
+// https://keepachangelog.com, which are recognized in addition to the
+// headings of the configured types.
+var keepAChangelogHeadings = map[string]string{
+	"Added":      "feature",
+	"Changed":    "enhancement",
+	"Deprecated": "deprecation",
+	"Removed":    "breaking-change",
+	"Fixed":      "bug",
+	"Security":   "bug",
+}
+
+// headingFlag collects HEADING=TYPE mappings from repeated flags.
+type headingFlag map[string]string
+
+func (h headingFlag) String() string {
+	var res []string
+	for heading, typ := range h {
+		res = append(res, heading+"="+typ)
+	}
+	sort.Strings(res)
+	return strings.Join(res, ",")
+}
+
+func (h headingFlag) Set(v string) error {
+	heading, typ, ok := strings.Cut(v, "=")
+	if !ok || strings.TrimSpace(heading) == "" || strings.TrimSpace(typ) == "" {
+		return errors.New("must be of the form HEADING=TYPE")
+	}
+	h[strings.TrimSpace(heading)] = strings.TrimSpace(typ)
+	return nil
+}
+
+var migrateCommand = &Command{
+	Name:     "migrate",
+	Args:     "[flags]",
+	Synopsis: "Convert an existing changelog into changelog entries",
+	Help: `
+Reads the notes of every release in an existing changelog file, and writes
+them to one entry file per issue in the entries directory. Entry files are
+named after the entry_pattern of the config and written in its format. When
+the pattern contains {component}, the notes of an issue are split into one
+entry per subcategory, which is used as the component.`,
+	Setup: setupMigrate,
+}
+
+func setupMigrate(g *Globals, fs *flag.FlagSet) func(args []string) int {
+	var changelogPath string
+	var dryRun, force bool
+	headings := headingFlag{}
+	fs.StringVar(&changelogPath, "changelog", "", "the path of the changelog file to migrate (defaults to CHANGELOG.md in the repository root)")
+	fs.Var(headings, "heading", "a HEADING=TYPE mapping of a section heading to the type of the notes under it; may be repeated")
+	fs.BoolVar(&dryRun, "dry-run", false, "print the entry files instead of writing them")
+	fs.BoolVar(&force, "force", false, "overwrite existing entry files")
+
+	return func(args []string) int {
+		if len(args) > 0 {
+			return usageError(fs, fmt.Sprintf("Unexpected arguments %q.", args))
+		}
+		cfg, err := g.openConfig()
+		if err != nil {
+			return fail(err)
+		}
+		entriesDir := g.EntriesDir
+		if changelogPath == "" {
+			changelogPath = cfg.ResolvePath("CHANGELOG.md")
+		}
+		if entriesDir == "" {
+			entriesDir = cfg.ResolvePath(cfg.EntriesDir)
+		}
+
+		src, err := os.ReadFile(changelogPath)
+		if err != nil {
+			return fail(err)
+		}
+
+		reg := cfg.TypeRegistry()
+		opts := parser.Options{
+			Headings: parser.DefaultHeadings(reg),
+			Prefixes: map[string]string{"Breaking": "breaking-change"},
+			Notes:    cfg.NoteParser(),
+		}
+		for heading, typ := range keepAChangelogHeadings {
+			if _, ok := opts.Headings[heading]; !ok && reg.Valid(typ) {
+				opts.Headings[heading] = typ
+			}
+		}
+		for heading, typ := range parser.DefaultPrefixes {
+			opts.Prefixes[heading] = typ
+		}
+		for heading, typ := range headings {
+			if !reg.Valid(typ) {
+				return usageError(fs, fmt.Sprintf("Unknown type %q for heading %q", typ, heading))
+			}
+			opts.Headings[heading] = reg.Canonical(typ)
+		}
+
+		// group the notes of every release by issue, and by component when
+		// entries are named after it
+		naming := cfg.EntryNaming()
+		type entryKey struct{ issue, component string }
+		byEntry := map[entryKey][]changelog.Note{}
+		var unattributed, unknown, uncategorized int
+		for _, rel := range parser.Parse(src, opts) {
+			for _, note := range rel.Notes {
+				key := entryKey{issue: note.Issue}
+				if naming.Uses("{component}") {
+					key.component = note.Subcategory
+				}
+				switch {
+				case !reg.Valid(note.Type):
+					unknown++
+					fmt.Fprintf(os.Stderr, "%s: unknown heading %q, use -heading to map it to a type: %s\n", rel.Version, note.Type, note.Body)
+				case note.Issue == "":
+					unattributed++
+					fmt.Fprintf(os.Stderr, "%s: no issue reference: %s\n", rel.Version, note.Body)
+				case naming.Uses("{component}") && key.component == "":
+					uncategorized++
+					fmt.Fprintf(os.Stderr, "%s: no subcategory to file the note under a component: %s\n", rel.Version, note.Body)
+				case !hasNote(byEntry[key], note):
+					byEntry[key] = append(byEntry[key], note)
+				}
+			}
+		}
+
+		keys := make([]entryKey, 0, len(byEntry))
+		for key := range byEntry {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].issue != keys[j].issue {
+				return keys[i].issue < keys[j].issue
+			}
+			return keys[i].component < keys[j].component
+		})
+
+		var written, skipped int
+		for _, key := range keys {
+			notes := byEntry[key]
+			name, err := naming.Name(key.issue, key.component, slugify(notes[0].Description))
+			if err != nil {
+				return fail(err)
+			}
+			path := filepath.Join(entriesDir, filepath.FromSlash(name))
+			body := entryBody(notes)
+			if format := naming.Format(); format != changelog.FormatText {
+				if body, err = changelog.FormatEntry("", notes, format); err != nil {
+					return fail(err)
+				}
+			}
+			if dryRun {
+				fmt.Printf("%s:\n%s\n", path, body)
+				written++
+				continue
+			}
+			if _, err := os.Stat(path); err == nil && !force {
+				g.Infof("Skipping %s, which already exists; use -force to overwrite it\n", path)
+				skipped++
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return fail(err)
+			}
+			if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+				return fail(err)
+			}
+			written++
+		}
+
+		skippedMsg := ""
+		if skipped > 0 {
+			skippedMsg = fmt.Sprintf(", skipped %d existing", skipped)
+		}
+		g.Infof("Migrated %d entries to %s%s\n", written, entriesDir, skippedMsg)
+		if unattributed > 0 {
+			fmt.Fprintf(os.Stderr, "%d notes without an issue reference were not migrated\n", unattributed)
+		}
+		if unknown > 0 {
+			fmt.Fprintf(os.Stderr, "%d notes under unknown headings were not migrated\n", unknown)
+		}
+		if uncategorized > 0 {
+			fmt.Fprintf(os.Stderr, "%d notes without a subcategory were not migrated\n", uncategorized)
+		}
+		if unattributed > 0 || unknown > 0 || uncategorized > 0 {
+			return ExitFailure
+		}
+		return ExitOK
+	}
+}
+
+// hasNote reports whether notes already holds a note of the same type and
+// body as n.
+func hasNote(notes []changelog.Note, n changelog.Note) bool {
+	for _, o := range notes {
+		if o.Type == n.Type && o.Body == n.Body {
+			return true
+		}
+	}
+	return false
+}
+
+// entryBody returns the contents of an entry file holding notes, one
+// release-note block per note.
+func entryBody(notes []changelog.Note) string {
+	blocks := make([]string, 0, len(notes))
+	for _, n := range notes {
+		blocks = append(blocks, fmt.Sprintf("```release-note:%s\n%s\n```\n", n.Type, n.Body))
+	}
+	return strings.Join(blocks, "\n")
+}
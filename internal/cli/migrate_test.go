@@ -0,0 +1,210 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/go-changelog"
+)
+
+const migrateChangelog = `# Changelog
+
+## 1.1.0 (October 18, 2026)
+
+FEATURES:
+
+* **New Resource:** ` + "`foo_bar`" + ` ([GH-11](https://github.com/o/r/issues/11))
+
+IMPROVEMENTS:
+
+* storage: Added a flag ([GH-12](https://github.com/o/r/issues/12))
+* network: Added a timeout ([GH-12](https://github.com/o/r/issues/12))
+
+BUG FIXES:
+
+* storage: Fixed a crash ([GH-13](https://github.com/o/r/issues/13))
+
+## 1.0.0 (January 2, 2026)
+
+### Fixed
+
+- core: Fixed a panic (#9)
+- storage: Fixed a crash ([GH-13](https://github.com/o/r/issues/13))
+`
+
+// migrate writes migrateChangelog and a config with the entry pattern to a
+// temporary directory, migrates it, and returns the exit code and the
+// config.
+func migrate(t *testing.T, pattern string) (int, *changelog.Config) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".changelog"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, ".changelog", "config.hcl")
+	config := ""
+	if pattern != "" {
+		config = "entry_pattern = \"" + pattern + "\"\n"
+	}
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "CHANGELOG.md"), []byte(migrateChangelog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	code := Main([]string{"-q", "-config", configPath, "migrate"})
+	cfg, err := changelog.LoadConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code, cfg
+}
+
+// migratedEntries returns the entry files in the entries directory of cfg,
+// by their path relative to it.
+func migratedEntries(t *testing.T, cfg *changelog.Config) map[string]string {
+	t.Helper()
+	entriesDir := cfg.ResolvePath(cfg.EntriesDir)
+	res := map[string]string{}
+	err := filepath.WalkDir(entriesDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || changelog.IsConfigFile(d.Name()) {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(entriesDir, path)
+		if err != nil {
+			return err
+		}
+		res[filepath.ToSlash(name)] = string(b)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestMigrateRoundTrip(t *testing.T) {
+	code, cfg := migrate(t, "")
+	if code != ExitOK {
+		t.Fatalf("migrate exited with %d, want %d", code, ExitOK)
+	}
+	// note is the part of a note the test compares.
+	type note struct{ typ, issue, body string }
+	var got []note
+	for name, body := range migratedEntries(t, cfg) {
+		for _, n := range cfg.NoteParser().NotesFromEntry(changelog.Entry{Issue: name, Body: body}) {
+			got = append(got, note{n.Type, n.Issue, n.Body})
+		}
+	}
+	sort.Slice(got, func(i, j int) bool {
+		if got[i].issue != got[j].issue {
+			return got[i].issue < got[j].issue
+		}
+		return got[i].body < got[j].body
+	})
+	want := []note{
+		{"new-resource", "11", "`foo_bar`"},
+		{"enhancement", "12", "network: Added a timeout"},
+		{"enhancement", "12", "storage: Added a flag"},
+		{"bug", "13", "storage: Fixed a crash"},
+		{"bug", "9", "core: Fixed a panic"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got the migrated notes %q, want %q", got, want)
+	}
+}
+
+func TestMigrateNaming(t *testing.T) {
+	cases := []struct {
+		name    string
+		pattern string
+		code    int
+		want    map[string]changelog.EntryFormat
+	}{
+		{
+			name: "default",
+			code: ExitOK,
+			want: map[string]changelog.EntryFormat{
+				"11.txt": changelog.FormatText,
+				"12.txt": changelog.FormatText,
+				"13.txt": changelog.FormatText,
+				"9.txt":  changelog.FormatText,
+			},
+		},
+		{
+			name:    "format",
+			pattern: "{issue}.yaml",
+			code:    ExitOK,
+			want: map[string]changelog.EntryFormat{
+				"11.yaml": changelog.FormatYAML,
+				"12.yaml": changelog.FormatYAML,
+				"13.yaml": changelog.FormatYAML,
+				"9.yaml":  changelog.FormatYAML,
+			},
+		},
+		{
+			name:    "slug",
+			pattern: "{issue}-{slug}",
+			code:    ExitOK,
+			want: map[string]changelog.EntryFormat{
+				"11-foo-bar.txt":         changelog.FormatText,
+				"12-added-a-timeout.txt": changelog.FormatText,
+				"13-fixed-a-crash.txt":   changelog.FormatText,
+				"9-fixed-a-panic.txt":    changelog.FormatText,
+			},
+		},
+		{
+			// the note without a subcategory can't be filed under a
+			// component
+			name:    "component",
+			pattern: "{component}/{issue}.json",
+			code:    ExitFailure,
+			want: map[string]changelog.EntryFormat{
+				"core/9.json":     changelog.FormatJSON,
+				"network/12.json": changelog.FormatJSON,
+				"storage/12.json": changelog.FormatJSON,
+				"storage/13.json": changelog.FormatJSON,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, cfg := migrate(t, tc.pattern)
+			if code != tc.code {
+				t.Errorf("migrate exited with %d, want %d", code, tc.code)
+			}
+			entries := migratedEntries(t, cfg)
+			got := map[string]changelog.EntryFormat{}
+			for name, body := range entries {
+				got[name] = changelog.EntryFormatOf(name)
+				entry := changelog.Entry{Issue: name, Body: body}
+				if diags := cfg.NoteParser().Diagnose(&entry); len(diags) > 0 {
+					t.Errorf("%s: %v", name, diags)
+				}
+				issue, component := cfg.EntryNaming().Parse(name)
+				for _, n := range cfg.NoteParser().NotesFromEntry(entry) {
+					if n.Issue != issue {
+						t.Errorf("%s: got a note for issue %q, want %q", name, n.Issue, issue)
+					}
+					if component != "" && n.Subcategory != component {
+						t.Errorf("%s: got a note with the subcategory %q, want %q", name, n.Subcategory, component)
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got the entry files %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,532 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/google/go-github/github"
+	"github.com/hashicorp/go-changelog"
+	"github.com/manifoldco/promptui"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed changelog-entry.tmpl
+var changelogTmplDefault string
+
+// entryNote is the data the entry template is executed with.
+type entryNote struct {
+	// service or area of codebase the pull request changes
+	Subcategory string
+	// release note type (Bug...)
+	Type string
+	// release note text
+	Description string
+	// pull request number
+	PR int
+	// URL of the pull request
+	URL string
+}
+
+// noteInput is a note of the entry being created, as read from -notes.
+type noteInput struct {
+	Type        string `yaml:"type"`
+	Subcategory string `yaml:"subcategory"`
+	Description string `yaml:"description"`
+}
+
+var newCommand = &Command{
+	Name:     "new",
+	Aliases:  []string{"entry"},
+	Args:     "[flags]",
+	Synopsis: "Create a changelog entry for a pull request",
+	Help: `
+Creates the changelog entry file of a pull request, prompting for the type,
+subcategory and description of its note when they aren't given as flags. The
+pull request is looked up on GitHub from the current branch unless -pr is
+set.
+
+An entry holding several notes is created by repeating -type and
+-description once for every note, or by reading the notes from a YAML or JSON
+list with -notes:
+
+  - type: bug
+    subcategory: storage
+    description: Fixed a crash when the bucket is empty
+
+The entry file is named after the entry_pattern of the config, such as
+{component}/{issue}-{slug}, and written in the format of its extension. The
+issue is the pull request number, the component is set by -component or
+defaults to the subcategory shared by the notes, and the slug is set by -slug
+or made from the first description.`,
+	Setup: setupNew,
+}
+
+func setupNew(g *Globals, fs *flag.FlagSet) func(args []string) int {
+	var changelogTmpl, url, allowedTypes, notesPath, component, slug string
+	var subcategories, changeTypes, descriptions stringsFlag
+	var pr int
+	var addURL, nonInteractive, appendEntry bool
+	fs.BoolVar(&addURL, "add-url", false, "add GitHub issue URL (omitted by default due to formatting in changelog-build)")
+	fs.IntVar(&pr, "pr", -1, "pull request number")
+	fs.Var(&subcategories, "subcategory", "the service or area of the codebase the pull request changes (optional); may be repeated once for every note, or given once for all of them")
+	fs.Var(&changeTypes, "type", "the type of change; may be repeated once for every note")
+	fs.Var(&descriptions, "description", "the changelog entry content; may be repeated once for every note")
+	fs.StringVar(&notesPath, "notes", "", "the path of a YAML or JSON list of the notes of the entry, with their type, subcategory and description, or - to read it from stdin")
+	fs.BoolVar(&nonInteractive, "non-interactive", false, "fail when the type or description of a note is missing instead of prompting for it")
+	fs.StringVar(&component, "component", "", "the component the entry is filed under, when the entry pattern of the config contains {component} (defaults to the subcategory of the notes)")
+	fs.StringVar(&slug, "slug", "", "the slug of the entry file name, when the entry pattern of the config contains {slug} (defaults to one made from the first description)")
+	fs.BoolVar(&appendEntry, "append", false, "add the notes to the existing entry file of the pull request instead of overwriting it (set -slug to name the same file when the entry pattern contains {slug})")
+	fs.StringVar(&changelogTmpl, "changelog-template", "", "the path of the file holding the template to use for the changelog entries")
+	fs.StringVar(&g.EntriesDir, "dir", "", "the relative path from the current directory of where the changelog entry file should be written; an alias of -entries-dir")
+	fs.StringVar(&allowedTypes, "allowed-types-file", "", "the relative path from the current directory to a line separated file of allowed types. If not provided, the configured or default types are used")
+
+	return func(args []string) int {
+		if len(args) > 0 {
+			return usageError(fs, fmt.Sprintf("Unexpected arguments %q.", args))
+		}
+		if notesPath != "" && (len(changeTypes) > 0 || len(descriptions) > 0 || len(subcategories) > 0) {
+			return usageError(fs, "Can't set -type, -subcategory or -description with -notes.")
+		}
+
+		var notes []noteInput
+		if notesPath != "" {
+			var err error
+			notes, err = readNotes(notesPath)
+			if err != nil {
+				return fail(err)
+			}
+		} else {
+			count := len(changeTypes)
+			if len(descriptions) > count {
+				count = len(descriptions)
+			}
+			if count == 0 {
+				count = 1
+			}
+			if nonInteractive && (len(changeTypes) != count || len(descriptions) != count) {
+				return usageError(fs, "Must specify the type and description of every note with -type and -description.")
+			}
+			if len(subcategories) > 1 && len(subcategories) != count {
+				return usageError(fs, "Must specify -subcategory once for every note, or once for all of them.")
+			}
+			notes = make([]noteInput, count)
+			for i := range notes {
+				if i < len(changeTypes) {
+					notes[i].Type = changeTypes[i]
+				}
+				if i < len(descriptions) {
+					notes[i].Description = descriptions[i]
+				}
+				if len(subcategories) == 1 {
+					notes[i].Subcategory = subcategories[0]
+				} else if i < len(subcategories) {
+					notes[i].Subcategory = subcategories[i]
+				}
+			}
+		}
+		// notes read from a file are never prompted for
+		interactive := !nonInteractive && notesPath == ""
+
+		pwd, err := os.Getwd()
+		if err != nil {
+			return fail(err)
+		}
+		cfg, err := g.openConfig()
+		if err != nil {
+			return fail(err)
+		}
+		dir := g.EntriesDir
+		if dir == "" {
+			dir = cfg.ResolvePath(cfg.EntriesDir)
+		}
+		if changelogTmpl == "" {
+			changelogTmpl = cfg.ResolvePath(cfg.Templates.Entry)
+		}
+
+		if pr == -1 {
+			repoDir := g.Repo
+			if repoDir == "" {
+				repoDir = pwd
+			}
+			pr, url, err = getPrNumberFromGithub(repoDir)
+			if err != nil {
+				return usageError(fs, fmt.Sprintf("Must specify pull request number or run in a git repo with a GitHub remote origin: %s", err))
+			}
+			g.Infof("Found matching pull request: %s\n", url)
+		}
+
+		types := cfg.TypeRegistry()
+		if allowedTypes != "" {
+			file, err := os.ReadFile(allowedTypes)
+			if err != nil {
+				return fail(fmt.Errorf("failed to read allowed types file: %w", err))
+			}
+
+			var allowed []changelog.Type
+			for _, name := range strings.Split(strings.TrimSpace(string(file)), "\n") {
+				allowed = append(allowed, changelog.Type{Name: strings.TrimSpace(name)})
+			}
+			types, err = changelog.NewTypeRegistry(allowed...)
+			if err != nil {
+				return fail(fmt.Errorf("failed to read allowed types file: %w", err))
+			}
+		}
+
+		for i := range notes {
+			n := &notes[i]
+			// invalid notes read from a file aren't usage errors
+			invalid := func(msg string) int {
+				if len(notes) > 1 {
+					msg = fmt.Sprintf("Note %d: %s", i+1, msg)
+				}
+				if notesPath != "" {
+					return fail(errors.New(msg))
+				}
+				return usageError(fs, msg)
+			}
+			label := ""
+			if len(notes) > 1 {
+				label = fmt.Sprintf(" of note %d", i+1)
+			}
+
+			switch {
+			case n.Type != "":
+				if !types.Valid(n.Type) {
+					return invalid(fmt.Sprintf("Unknown type %q, must be one of %v.", n.Type, types.Names(false)))
+				}
+				n.Type = types.Canonical(n.Type)
+			case !interactive:
+				return invalid("Must specify the change type.")
+			default:
+				prompt := promptui.Select{
+					Label: "Select a change type" + label,
+					Items: types.Names(false),
+				}
+				_, n.Type, err = prompt.Run()
+				if err != nil {
+					return invalid("Must specify the change type.")
+				}
+			}
+
+			switch {
+			case n.Subcategory != "":
+				if len(cfg.Subcategories) > 0 && !contains(cfg.Subcategories, n.Subcategory) {
+					return invalid(fmt.Sprintf("Unknown subcategory %q, must be one of %v.", n.Subcategory, cfg.Subcategories))
+				}
+			case !interactive || len(subcategories) > 0:
+			case len(cfg.Subcategories) > 0:
+				prompt := promptui.Select{
+					Label: "Select a subcategory" + label,
+					Items: append([]string{"(none)"}, cfg.Subcategories...),
+				}
+				var sel int
+				sel, n.Subcategory, _ = prompt.Run()
+				if sel == 0 {
+					n.Subcategory = ""
+				}
+			default:
+				prompt := promptui.Prompt{Label: "Subcategory" + label + " (optional)"}
+				n.Subcategory, _ = prompt.Run()
+			}
+
+			if n.Description == "" {
+				if !interactive {
+					return invalid("Must specify the change description.")
+				}
+				prompt := promptui.Prompt{Label: "Description" + label}
+				n.Description, err = prompt.Run()
+				if err != nil || n.Description == "" {
+					return invalid("Must specify the change description.")
+				}
+			}
+		}
+
+		naming := cfg.EntryNaming()
+		if naming.Uses("{component}") && component == "" {
+			component = sharedSubcategory(notes)
+			if component == "" && interactive {
+				prompt := promptui.Prompt{Label: "Component"}
+				component, _ = prompt.Run()
+			}
+			if component == "" {
+				return usageError(fs, fmt.Sprintf("Must specify the component of the entry with -component, as the entry pattern is %s.", naming))
+			}
+		}
+		if naming.Uses("{slug}") && slug == "" {
+			slug = slugify(notes[0].Description)
+		}
+		name, err := naming.Name(strconv.Itoa(pr), component, slug)
+		if err != nil {
+			return fail(err)
+		}
+		entryPath := filepath.Join(dir, filepath.FromSlash(name))
+		if !filepath.IsAbs(dir) {
+			entryPath = filepath.Join(pwd, entryPath)
+		}
+		var existing string
+		if appendEntry {
+			file, err := os.ReadFile(entryPath)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fail(err)
+			}
+			existing = string(file)
+		}
+		appended := strings.TrimSpace(existing) != ""
+
+		if !addURL {
+			url = ""
+		}
+		var body, out string
+		if format := naming.Format(); format != changelog.FormatText {
+			// structured entries have no template, and the URL of the pull
+			// request is rendered from its issue by changelog-build
+			entryNotes := make([]changelog.Note, 0, len(notes))
+			for _, n := range notes {
+				text := n.Description
+				if n.Subcategory != "" {
+					text = n.Subcategory + ": " + text
+				}
+				entryNotes = append(entryNotes, changelog.Note{Type: n.Type, Body: text})
+			}
+			if body, err = changelog.FormatEntry("", entryNotes, format); err != nil {
+				return fail(err)
+			}
+			out = body
+			if appended {
+				if out, err = changelog.FormatEntry(existing, entryNotes, format); err != nil {
+					return fail(fmt.Errorf("error adding to %s: %w", entryPath, err))
+				}
+			}
+		} else {
+			if body, err = renderEntry(changelogTmpl, notes, pr, url); err != nil {
+				return fail(err)
+			}
+			out = body
+			if appended {
+				out = strings.TrimRight(existing, "\n") + "\n\n" + body
+			}
+		}
+		fmt.Printf("\n%s\n", body)
+
+		if err := os.MkdirAll(filepath.Dir(entryPath), 0755); err != nil {
+			return fail(err)
+		}
+		if err := os.WriteFile(entryPath, []byte(out), 0644); err != nil {
+			return fail(err)
+		}
+		if appended {
+			g.Infof("Added %d notes to the changelog entry at %s\n", len(notes), entryPath)
+		} else {
+			g.Infof("Created changelog entry at %s\n", entryPath)
+		}
+		return ExitOK
+	}
+}
+
+// renderEntry renders the text entry holding notes with the template at
+// path, or the default template if path is empty.
+func renderEntry(path string, notes []noteInput, pr int, url string) (string, error) {
+	src := changelogTmplDefault
+	if path != "" {
+		file, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		src = string(file)
+	}
+	tmpl, err := template.New("").Parse(src)
+	if err != nil {
+		return "", fmt.Errorf("error parsing the entry template: %w", err)
+	}
+
+	// the template renders a single note, so each note is rendered on its
+	// own and separated by a blank line
+	blocks := make([]string, 0, len(notes))
+	for _, n := range notes {
+		var buf bytes.Buffer
+		err := tmpl.Execute(&buf, entryNote{Type: n.Type, Description: n.Description, Subcategory: n.Subcategory, PR: pr, URL: url})
+		if err != nil {
+			return "", fmt.Errorf("error executing the entry template: %w", err)
+		}
+		blocks = append(blocks, strings.TrimRight(buf.String(), "\n")+"\n")
+	}
+	return strings.Join(blocks, "\n"), nil
+}
+
+// sharedSubcategory returns the subcategory of notes if they all have the
+// same one, or "".
+func sharedSubcategory(notes []noteInput) string {
+	for _, n := range notes[1:] {
+		if n.Subcategory != notes[0].Subcategory {
+			return ""
+		}
+	}
+	return notes[0].Subcategory
+}
+
+var slugSeparatorRE = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify returns a slug made of the first words of description, such as
+// fixed-a-crash-when-the for "Fixed a crash when the bucket is empty".
+func slugify(description string) string {
+	words := strings.Fields(slugSeparatorRE.ReplaceAllString(strings.ToLower(description), " "))
+	if len(words) > 5 {
+		words = words[:5]
+	}
+	return strings.Join(words, "-")
+}
+
+// readNotes reads the notes of an entry from the YAML or JSON list at path,
+// or stdin if path is "-".
+func readNotes(path string) ([]noteInput, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	} else {
+		path = "stdin"
+	}
+	// JSON is a subset of YAML, so both are decoded as YAML
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	var notes []noteInput
+	if err := dec.Decode(&notes); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error reading the notes from %s: %w", path, err)
+	}
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("no notes found in %s", path)
+	}
+	return notes, nil
+}
+
+// contains reports whether list holds s.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// openGit opens the git repository at path or one of its parents.
+func openGit(path string) (*git.Repository, error) {
+	r, err := git.PlainOpen(path)
+	if err != nil {
+		if path == "/" {
+			return r, err
+		}
+		return openGit(path[:strings.LastIndex(path, "/")])
+	}
+	return r, err
+}
+
+// getPrNumberFromGithub returns the number and URL of the open pull request
+// of the current branch of the repository at path.
+func getPrNumberFromGithub(path string) (int, string, error) {
+	r, err := openGit(path)
+	if err != nil {
+		return -1, "", err
+	}
+
+	ref, err := r.Head()
+	if err != nil {
+		return -1, "", err
+	}
+
+	localBranch, err := r.Branch(ref.Name().Short())
+	if err != nil {
+		return -1, "", err
+	}
+
+	remote, err := r.Remote("origin")
+	if err != nil {
+		return -1, "", err
+	}
+
+	if len(remote.Config().URLs) <= 0 {
+		return -1, "", errors.New("not able to parse repo and org")
+	}
+	remoteUrl := remote.Config().URLs[0]
+
+	re := regexp.MustCompile(`.*github\.com:(.*)/(.*)\.git`)
+	m := re.FindStringSubmatch(remoteUrl)
+	if len(m) < 3 {
+		return -1, "", errors.New("not able to parse repo and org")
+	}
+
+	cli := github.NewClient(nil)
+
+	ctx := context.Background()
+
+	githubOrg := m[1]
+	githubRepo := m[2]
+
+	opt := &github.PullRequestListOptions{
+		ListOptions: github.ListOptions{PerPage: 200},
+		Sort:        "updated",
+		Direction:   "desc",
+	}
+
+	list, _, err := cli.PullRequests.List(ctx, githubOrg, githubRepo, opt)
+	if err != nil {
+		return -1, "", err
+	}
+
+	for _, pr := range list {
+		head := pr.GetHead()
+		if head == nil {
+			continue
+		}
+
+		branch := head.GetRef()
+		if branch == "" {
+			continue
+		}
+
+		repo := head.GetRepo()
+		if repo == nil {
+			continue
+		}
+
+		// Allow finding PRs from forks - localBranch.Remote will return the
+		// remote name for branches of origin, but the remote URL for forks
+		var gitRemote string
+		remote, err := r.Remote(localBranch.Remote)
+		if err != nil {
+			gitRemote = localBranch.Remote
+		} else {
+			gitRemote = remote.Config().URLs[0]
+		}
+
+		if (gitRemote == *repo.SSHURL || gitRemote == *repo.CloneURL) &&
+			localBranch.Name == branch {
+			n := pr.GetNumber()
+
+			if n != 0 {
+				return n, pr.GetHTMLURL(), nil
+			}
+		}
+	}
+
+	return -1, "", errors.New("no match found")
+}
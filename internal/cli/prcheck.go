@@ -0,0 +1,136 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/github"
+	"github.com/hashicorp/go-changelog"
+	"golang.org/x/oauth2"
+)
+
+var prCheckCommand = &Command{
+	Name:     "pr-check",
+	Aliases:  []string{"pr-body-check"},
+	Args:     "[flags] PR#",
+	Synopsis: "Check the changelog entries in the body of a pull request",
+	Help: `
+Checks that the body of a GitHub pull request has at least one changelog
+entry, and that all of them are valid, and comments on the pull request to
+list the problems found otherwise. GITHUB_TOKEN must hold a token allowed to
+read and comment on the pull request.`,
+	Setup: setupPRCheck,
+}
+
+func setupPRCheck(g *Globals, fs *flag.FlagSet) func(args []string) int {
+	var githubRepo string
+	fs.StringVar(&githubRepo, "github-repo", "", "the OWNER/NAME of the GitHub repository of the pull request (defaults to $GITHUB_OWNER/$GITHUB_REPO)")
+
+	return func(args []string) int {
+		if len(args) != 1 {
+			return usageError(fs, "Must specify the number of the pull request to check.")
+		}
+		pr := args[0]
+		prNo, err := strconv.Atoi(pr)
+		if err != nil {
+			return usageError(fs, fmt.Sprintf("Error parsing PR %q as a number: %s", pr, err))
+		}
+
+		owner, repo, ok := strings.Cut(githubRepo, "/")
+		if githubRepo == "" {
+			owner, repo, ok = os.Getenv("GITHUB_OWNER"), os.Getenv("GITHUB_REPO"), true
+		}
+		if !ok || owner == "" || repo == "" {
+			return usageError(fs, "Must specify the repository of the pull request with -github-repo, or GITHUB_OWNER and GITHUB_REPO.")
+		}
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return usageError(fs, "GITHUB_TOKEN not set")
+		}
+
+		cfg, err := g.openConfig()
+		if err != nil {
+			return fail(fmt.Errorf("error loading changelog config: %w", err))
+		}
+		return checkPR(cfg, token, owner, repo, prNo)
+	}
+}
+
+// checkPR checks the changelog entries in the body of the pull request
+// prNo of the GitHub repository owner/repo, and comments on it if there are
+// problems, authenticating with token.
+func checkPR(cfg *changelog.Config, token, owner, repo string, prNo int) int {
+	ctx := context.Background()
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: token},
+	)
+	tc := oauth2.NewClient(ctx, ts)
+
+	client := github.NewClient(tc)
+
+	pullRequest, _, err := client.PullRequests.Get(ctx, owner, repo, prNo)
+	if err != nil {
+		return fail(fmt.Errorf("error retrieving pull request github.com/%s/%s/%d: %w", owner, repo, prNo, err))
+	}
+
+	entry := changelog.Entry{
+		Issue: strconv.Itoa(prNo),
+		Body:  pullRequest.GetBody(),
+	}
+
+	diags := cfg.NoteParser().Diagnose(&entry)
+	for _, d := range diags {
+		fmt.Fprintf(os.Stderr, "changelog entry in %s: %s\n", entry.Issue, d)
+	}
+	if !diags.HasErrors() {
+		return ExitOK
+	}
+
+	var body string
+	if len(diags.WithCode(changelog.EntryErrorNotFound)) > 0 {
+		body = "Oops! It looks like no changelog entry is attached to" +
+			" this PR. Please include a release note block" +
+			" in the PR body, as described in https://github.com/GoogleCloudPlatform/magic-modules/blob/master/.ci/RELEASE_NOTES_GUIDE.md:" +
+			"\n\n~~~\n```release-note:TYPE\nRelease note" +
+			"\n```\n~~~"
+	} else {
+		body = "Oops! It looks like there are problems with the changelog entries in this PR:"
+		for _, d := range diags {
+			if d.Severity == changelog.SeverityError {
+				body += "\n* " + commentLocation(d) + d.Message
+			}
+		}
+		body += "\n\nPlease fix them as described in https://github.com/GoogleCloudPlatform/magic-modules/blob/master/.ci/RELEASE_NOTES_GUIDE.md."
+	}
+	_, _, err = client.Issues.CreateComment(ctx, owner, repo,
+		prNo, &github.IssueComment{
+			Body: &body,
+		})
+	if err != nil {
+		return fail(fmt.Errorf("error creating pull request comment on github.com/%s/%s/%d: %w", owner, repo, prNo, err))
+	}
+	return ExitFailure
+}
+
+// commentLocation returns where d was found in the pull request body, such as
+// "release note 2 (line 5): ", for the comment listing the problems. The
+// note is left out when d isn't about a single note, and the line when it is
+// unknown.
+func commentLocation(d changelog.Diagnostic) string {
+	switch {
+	case d.Note >= 0 && d.Line > 0:
+		return fmt.Sprintf("release note %d (line %d): ", d.Note+1, d.Line)
+	case d.Note >= 0:
+		return fmt.Sprintf("release note %d: ", d.Note+1)
+	case d.Line > 0:
+		return fmt.Sprintf("line %d: ", d.Line)
+	}
+	return ""
+}
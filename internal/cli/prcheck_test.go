@@ -0,0 +1,30 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-changelog"
+)
+
+func TestCommentLocation(t *testing.T) {
+	cases := []struct {
+		name string
+		d    changelog.Diagnostic
+		want string
+	}{
+		{name: "note and line", d: changelog.Diagnostic{Note: 1, Line: 5}, want: "release note 2 (line 5): "},
+		{name: "note without line", d: changelog.Diagnostic{Note: 0}, want: "release note 1: "},
+		{name: "fence outside notes", d: changelog.Diagnostic{Note: -1, Line: 3}, want: "line 3: "},
+		{name: "whole entry", d: changelog.Diagnostic{Note: -1}, want: ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := commentLocation(tc.d); got != tc.want {
+				t.Errorf("commentLocation(%+v) = %q, want %q", tc.d, got, tc.want)
+			}
+		})
+	}
+}
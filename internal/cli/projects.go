@@ -0,0 +1,176 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-changelog"
+	"github.com/hashicorp/go-changelog/internal/render"
+)
+
+// projectOptions holds the flags that apply to every project built.
+type projectOptions struct {
+	lastRelease, thisRelease, repoDir   string
+	presetName, noteTmpl, changelogTmpl string
+	format, version                     string
+	date                                time.Time
+	localFS, amended, prereleases       bool
+	write, force                        bool
+}
+
+// project is a project being built.
+type project struct {
+	name     string
+	cfg      *changelog.Config
+	since    string
+	version  string
+	renderer *render.Renderer
+
+	// changelog is the path of the project's changelog file, or empty if
+	// it has none.
+	changelog string
+}
+
+// buildProjects builds the changelogs of the named projects of cfg, diffing
+// their entries in a single walk of the history of opts.thisRelease.
+func buildProjects(g *Globals, cfg *changelog.Config, names []string, opts projectOptions) error {
+	if opts.version != "" && len(names) > 1 {
+		return errors.New("can't set -version when building more than one project, the version of each is taken from its release tag at -this-release")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	// the release tags are looked up in the repository the entries are
+	// diffed in, which may be a clone of a remote one
+	r, err := openRepository(ctx, opts.repoDir, opts.localFS)
+	if err != nil {
+		return err
+	}
+
+	projects := make([]project, 0, len(names))
+	ranges := make([]changelog.DiffRange, 0, len(names))
+	for _, name := range names {
+		pcfg, err := cfg.Project(name)
+		if err != nil {
+			return err
+		}
+		p := project{name: name, cfg: pcfg, since: opts.lastRelease, version: opts.version}
+		for _, pc := range cfg.Projects {
+			if pc.Name == name && pc.Changelog != "" {
+				p.changelog = cfg.ResolvePath(pc.Changelog)
+			}
+		}
+		tagOpts := changelog.ReleaseTagOptions{
+			Pattern:            pcfg.TagPattern,
+			IncludePrereleases: opts.prereleases,
+		}
+
+		if p.since == "auto" {
+			p.since, err = changelog.PreviousRelease(r, opts.thisRelease, tagOpts)
+			if errors.Is(err, changelog.ErrNoPreviousRelease) {
+				g.Infof("No release of %s found before %s, including all entries.\n", name, opts.thisRelease)
+				p.since = "-"
+			} else if err != nil {
+				return fmt.Errorf("project %s: %w", name, err)
+			} else {
+				g.Infof("Using %s as the last release of %s.\n", p.since, name)
+			}
+		}
+		if p.version == "" {
+			tag, err := changelog.ReleaseTagAt(r, opts.thisRelease, tagOpts)
+			if err != nil {
+				return fmt.Errorf("project %s: %w", name, err)
+			}
+			p.version = strings.TrimPrefix(tag[strings.LastIndex(tag, "/")+1:], "v")
+		}
+		if opts.write {
+			if p.changelog == "" {
+				return fmt.Errorf("project %s has no changelog file configured", name)
+			}
+			if p.version == "" {
+				return fmt.Errorf("project %s has no release tag at %s, so its version is unknown", name, opts.thisRelease)
+			}
+		}
+
+		if opts.format == "text" {
+			noteTmpl, changelogTmpl, presetName := opts.noteTmpl, opts.changelogTmpl, opts.presetName
+			if noteTmpl == "" {
+				noteTmpl = pcfg.ResolvePath(pcfg.Templates.ReleaseNote)
+			}
+			if changelogTmpl == "" {
+				changelogTmpl = pcfg.ResolvePath(pcfg.Templates.Changelog)
+			}
+			if presetName == "" {
+				presetName = pcfg.Templates.Preset
+			}
+			templates, err := render.LoadTemplates(presetName, changelogTmpl, noteTmpl)
+			if err != nil {
+				return fmt.Errorf("project %s: %w", name, err)
+			}
+			p.renderer, err = render.New(templates, pcfg)
+			if err != nil {
+				return fmt.Errorf("project %s: %w", name, err)
+			}
+		}
+
+		g.Debugf("Diffing the entries of %s in %s since %s.\n", name, pcfg.EntriesDir, p.since)
+		projects = append(projects, p)
+		ranges = append(ranges, changelog.DiffRange{Since: p.since, Dir: pcfg.EntriesDir, Naming: entryNaming(pcfg)})
+	}
+
+	entries, err := changelog.DiffRepositoryRanges(ctx, r, opts.thisRelease, ranges)
+	if err != nil {
+		return err
+	}
+
+	releases := make(map[string]*changelog.Release, len(projects))
+	for i, p := range projects {
+		for j := 0; j < entries[i].Len(); j++ {
+			entry := entries[i].Get(j)
+			if entry.Status == changelog.EntryRemoved {
+				fmt.Fprintf(os.Stderr, "Warning: changelog entry %s of %s was removed in %s\n", changelog.TrimEntryExtension(entry.Issue), p.name, entry.Hash)
+			}
+		}
+		release := changelog.NewReleaseWith(entries[i], p.cfg.NoteParser(), opts.amended)
+		release.Version = p.version
+		release.Date = opts.date
+		releases[p.name] = release
+	}
+
+	if opts.format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(releases); err != nil {
+			return fmt.Errorf("error encoding releases: %w", err)
+		}
+		return nil
+	}
+
+	for _, p := range projects {
+		out, err := p.renderer.Render(releases[p.name])
+		if err != nil {
+			return fmt.Errorf("project %s: %w", p.name, err)
+		}
+		if !opts.write {
+			if len(projects) > 1 {
+				fmt.Printf("==> %s <==\n", p.name)
+			}
+			os.Stdout.Write(out)
+			continue
+		}
+		if err := writeSection(p.changelog, p.version, opts.date, out, opts.force); err != nil {
+			return err
+		}
+		g.Infof("Updated the %s section of %s\n", p.version, p.changelog)
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/hashicorp/go-changelog"
+	"github.com/hashicorp/go-changelog/internal/render"
+	"github.com/hashicorp/go-changelog/parser"
+)
+
+var verifyCommand = &Command{
+	Name:     "verify",
+	Args:     "[flags]",
+	Synopsis: "Check that a changelog matches the entries of each release",
+	Help: `
+Renders the notes of every release in a changelog file from the changelog
+entries between its release tag and the previous one, and reports the notes
+that are missing from, extra in, or reworded in the changelog.`,
+	Setup: setupVerify,
+}
+
+func setupVerify(g *Globals, fs *flag.FlagSet) func(args []string) int {
+	var changelogPath, tagPattern, presetName, noteTmpl, changelogTmpl, version string
+	var prereleases, amended bool
+	fs.StringVar(&changelogPath, "changelog", "", "the path of the changelog file to verify (defaults to CHANGELOG.md in the repository root)")
+	fs.StringVar(&g.Repo, "git-dir", "", "the directory of the git repo being released; an alias of -repo")
+	fs.StringVar(&tagPattern, "tag-pattern", "", "the glob release tags match (defaults to \"v*\")")
+	fs.BoolVar(&prereleases, "include-prereleases", false, "allow prerelease tags to be chosen as the previous release of a version")
+	fs.StringVar(&presetName, "preset", "", fmt.Sprintf("the built-in templates to use when no template paths are given, one of %v (defaults to %q)", render.PresetNames(), render.DefaultPreset))
+	fs.StringVar(&noteTmpl, "note-template", "", "the path of the file holding the template to use for each item in the changelog")
+	fs.StringVar(&changelogTmpl, "changelog-template", "", "the path of the file holding the template to use for the entire changelog")
+	fs.BoolVar(&amended, "amended", false, "expect the notes of entries modified since the previous release, as rendered by changelog-build -amended")
+	fs.StringVar(&version, "version", "", "only verify the section for this version")
+
+	return func(args []string) int {
+		if len(args) > 0 {
+			return usageError(fs, fmt.Sprintf("Unexpected arguments %q.", args))
+		}
+		cfg, err := g.openConfig()
+		if err != nil {
+			return fail(err)
+		}
+		repoDir := g.Repo
+		if repoDir == "" {
+			repoDir = cfg.Root
+		}
+		if changelogPath == "" {
+			changelogPath = cfg.ResolvePath("CHANGELOG.md")
+		}
+		entriesDir := g.EntriesDir
+		if entriesDir == "" {
+			entriesDir = cfg.EntriesDir
+		}
+		if noteTmpl == "" {
+			noteTmpl = cfg.ResolvePath(cfg.Templates.ReleaseNote)
+		}
+		if changelogTmpl == "" {
+			changelogTmpl = cfg.ResolvePath(cfg.Templates.Changelog)
+		}
+		if presetName == "" {
+			presetName = cfg.Templates.Preset
+		}
+		if tagPattern == "" {
+			tagPattern = cfg.TagPattern
+		}
+		tagOpts := changelog.ReleaseTagOptions{
+			Pattern:            tagPattern,
+			IncludePrereleases: prereleases,
+		}
+
+		templates, err := render.LoadTemplates(presetName, changelogTmpl, noteTmpl)
+		if err != nil {
+			return usageError(fs, err.Error())
+		}
+		renderer, err := render.New(templates, cfg)
+		if err != nil {
+			return fail(err)
+		}
+
+		src, err := os.ReadFile(changelogPath)
+		if err != nil {
+			return fail(err)
+		}
+		doc := changelog.ParseDocument(src)
+
+		r, err := git.PlainOpen(repoDir)
+		if err != nil {
+			return fail(fmt.Errorf("error opening repository at %q: %w", repoDir, err))
+		}
+
+		reg := cfg.TypeRegistry()
+		parseOpts := parser.Options{Headings: parser.DefaultHeadings(reg), Notes: cfg.NoteParser()}
+		var verified, drifted int
+		for _, s := range doc.Sections {
+			if version != "" && !sameVersion(s.Version, version) {
+				continue
+			}
+			tag, err := changelog.ReleaseTag(r, s.Version, tagOpts)
+			if err != nil {
+				return fail(err)
+			}
+			if tag == "" {
+				g.Infof("Skipping %s, which has no release tag.\n", s.Version)
+				continue
+			}
+			lastRelease, err := changelog.PreviousRelease(r, tag, tagOpts)
+			if errors.Is(err, changelog.ErrNoPreviousRelease) {
+				lastRelease = "-"
+			} else if err != nil {
+				return fail(err)
+			}
+			g.Debugf("Verifying %s against the entries from %s to %s.\n", s.Version, lastRelease, tag)
+			ranges := []changelog.DiffRange{{Since: lastRelease, Dir: entriesDir, Naming: entryNaming(cfg)}}
+			diffed, err := changelog.DiffRepositoryRanges(context.Background(), r, tag, ranges)
+			if err != nil {
+				return fail(err)
+			}
+			entries := diffed[0]
+			release := changelog.NewReleaseWith(entries, cfg.NoteParser(), amended)
+			release.Version = s.Version
+			out, err := renderer.Render(release)
+			if err != nil {
+				return fail(err)
+			}
+
+			want := parser.ParseSection(out, parseOpts)
+			got := parser.ParseSection(s.Body, parseOpts)
+			diffs := compareNotes(want.Notes, got.Notes)
+			diffs = append(diffs, compareNotes(want.AmendedNotes, got.AmendedNotes)...)
+			verified++
+			if len(diffs) == 0 {
+				continue
+			}
+			drifted++
+			for _, d := range diffs {
+				fmt.Printf("%s (line %d): %s\n", s.Version, s.Line, d)
+			}
+		}
+
+		if version != "" && verified == 0 {
+			fmt.Fprintf(os.Stderr, "No release section found for %s in %s.\n", version, changelogPath)
+			return ExitFailure
+		}
+		g.Infof("Verified %d releases in %s, %d drifted from the changelog entries.\n", verified, changelogPath, drifted)
+		if drifted > 0 {
+			return ExitFailure
+		}
+		return ExitOK
+	}
+}
+
+// noteDiff is a difference between the notes rendered from the changelog
+// entries of a release and the notes in its changelog section.
+type noteDiff struct {
+	// want is the note rendered from the entries, or nil if the note is
+	// only in the changelog.
+	want *changelog.Note
+
+	// got is the note in the changelog, or nil if the note is missing
+	// from it.
+	got *changelog.Note
+}
+
+func (d noteDiff) String() string {
+	switch {
+	case d.got == nil:
+		return fmt.Sprintf("missing %s note for %s: %s", d.want.Type, issueName(d.want.Issue), d.want.Body)
+	case d.want == nil:
+		return fmt.Sprintf("extra %s note for %s: %s", d.got.Type, issueName(d.got.Issue), d.got.Body)
+	default:
+		return fmt.Sprintf("reworded %s note for %s:\n  entries:   %s\n  changelog: %s", d.want.Type, issueName(d.want.Issue), d.want.Body, d.got.Body)
+	}
+}
+
+// issueName returns the name of issue in diff output.
+func issueName(issue string) string {
+	if issue == "" {
+		return "no issue"
+	}
+	return "issue " + issue
+}
+
+// compareNotes returns the differences between the notes rendered from the
+// entries of a release, want, and the notes in the changelog, got. Notes
+// are equal if they have the same type, issue and body, ignoring
+// differences in whitespace. A note for the same type and issue as a
+// missing one is reported as reworded.
+func compareNotes(want, got []changelog.Note) []noteDiff {
+	key := func(n changelog.Note) string {
+		return n.Type + "\x00" + n.Issue + "\x00" + strings.Join(strings.Fields(n.Body), " ")
+	}
+	matched := make([]bool, len(got))
+	var missing []int
+	for i, w := range want {
+		found := false
+		for j, g := range got {
+			if !matched[j] && key(w) == key(g) {
+				matched[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, i)
+		}
+	}
+
+	var res []noteDiff
+	for _, i := range missing {
+		w := want[i]
+		d := noteDiff{want: &want[i]}
+		for j, g := range got {
+			if !matched[j] && w.Issue != "" && g.Type == w.Type && g.Issue == w.Issue {
+				matched[j] = true
+				d.got = &got[j]
+				break
+			}
+		}
+		res = append(res, d)
+	}
+	for j := range got {
+		if !matched[j] {
+			res = append(res, noteDiff{got: &got[j]})
+		}
+	}
+	return res
+}
+
+// sameVersion reports whether the changelog headings a and b are the same
+// version, comparing them as semantic versions when they both are.
+func sameVersion(a, b string) bool {
+	if a == b {
+		return true
+	}
+	v := changelog.TagVersion(a)
+	return v != "" && v == changelog.TagVersion(b)
+}
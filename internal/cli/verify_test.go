@@ -0,0 +1,27 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import "testing"
+
+func TestSameVersion(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "1.2.0", b: "1.2.0", want: true},
+		{a: "1.2.0", b: "v1.2.0", want: true},
+		{a: "1.2", b: "v1.2.0", want: true},
+		{a: "1.2.0", b: "1.3.0"},
+		{a: "Unreleased", b: "Unreleased", want: true},
+		{a: "Unreleased", b: "1.2.0"},
+		{a: "Unreleased", b: "main"},
+		{a: "1.2.0", b: "main"},
+	}
+	for _, tc := range cases {
+		if got := sameVersion(tc.a, tc.b); got != tc.want {
+			t.Errorf("sameVersion(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
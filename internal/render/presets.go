@@ -0,0 +1,57 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package render
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+)
+
+//go:embed changelog.tmpl release-note.tmpl presets
+var presetFS embed.FS
+
+// DefaultPreset is the preset used when no templates are configured.
+const DefaultPreset = "hashicorp"
+
+// presetDirs maps the name of each preset to the directory of presetFS
+// holding its templates. The HashiCorp preset is made of the templates at
+// the root of this package, which predate presets.
+var presetDirs = map[string]string{
+	"hashicorp":      ".",
+	"keepachangelog": "presets/keepachangelog",
+	"github":         "presets/github",
+	"plain":          "presets/plain",
+}
+
+// PresetNames returns the names of the built-in presets, sorted.
+func PresetNames() []string {
+	names := make([]string, 0, len(presetDirs))
+	for name := range presetDirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadPreset returns the templates of the built-in preset name.
+func LoadPreset(name string) (Templates, error) {
+	dir, ok := presetDirs[name]
+	if !ok {
+		return Templates{}, fmt.Errorf("unknown preset %q, must be one of %v", name, PresetNames())
+	}
+	changelog, err := presetFS.ReadFile(path.Join(dir, "changelog.tmpl"))
+	if err != nil {
+		return Templates{}, err
+	}
+	releaseNote, err := presetFS.ReadFile(path.Join(dir, "release-note.tmpl"))
+	if err != nil {
+		return Templates{}, err
+	}
+	return Templates{
+		Changelog:   string(changelog),
+		ReleaseNote: string(releaseNote),
+	}, nil
+}
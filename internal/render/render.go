@@ -0,0 +1,187 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package render renders releases with the changelog templates shared by the
+// changelog commands.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/go-changelog"
+)
+
+// Templates holds the contents of the templates a release is rendered with.
+type Templates struct {
+	// Changelog is the template executed with the release.
+	Changelog string
+
+	// ReleaseNote defines the "note" template used to render each note.
+	ReleaseNote string
+
+	// ChangelogName and ReleaseNoteName are the base names of the files
+	// the templates were loaded from, if any. The templates can also be
+	// referred to by these names, as in {{template "release-note.tmpl" .}}.
+	ChangelogName   string
+	ReleaseNoteName string
+}
+
+// LoadTemplates returns the templates of the built-in preset, replacing
+// either of them with the contents of the file at changelogPath or
+// releaseNotePath when they aren't empty. If preset is empty, DefaultPreset
+// is used.
+func LoadTemplates(preset, changelogPath, releaseNotePath string) (Templates, error) {
+	if preset == "" {
+		preset = DefaultPreset
+	}
+	t, err := LoadPreset(preset)
+	if err != nil {
+		return Templates{}, err
+	}
+	if releaseNotePath != "" {
+		b, err := os.ReadFile(releaseNotePath)
+		if err != nil {
+			return Templates{}, fmt.Errorf("error reading template: %w", err)
+		}
+		t.ReleaseNote = string(b)
+		t.ReleaseNoteName = filepath.Base(releaseNotePath)
+	}
+	if changelogPath != "" {
+		b, err := os.ReadFile(changelogPath)
+		if err != nil {
+			return Templates{}, fmt.Errorf("error reading template: %w", err)
+		}
+		t.Changelog = string(b)
+		t.ChangelogName = filepath.Base(changelogPath)
+	}
+	return t, nil
+}
+
+// Renderer renders releases with a set of parsed templates.
+type Renderer struct {
+	tmpl *template.Template
+}
+
+// New parses t, with the template functions bound to cfg, and returns a
+// Renderer using them.
+func New(t Templates, cfg *changelog.Config) (*Renderer, error) {
+	tmpl := template.New("changelog").Funcs(Funcs(cfg))
+	if _, err := tmpl.New("release-note").Parse(t.ReleaseNote); err != nil {
+		return nil, fmt.Errorf("error parsing the release note template: %w", err)
+	}
+	if _, err := tmpl.Parse(t.Changelog); err != nil {
+		return nil, fmt.Errorf("error parsing the changelog template: %w", err)
+	}
+	for name, file := range map[string]string{"release-note": t.ReleaseNoteName, "changelog": t.ChangelogName} {
+		if file == "" || file == name {
+			continue
+		}
+		if tree := tmpl.Lookup(name).Tree; tree != nil {
+			if _, err := tmpl.AddParseTree(file, tree); err != nil {
+				return nil, fmt.Errorf("error naming the %s template %s: %w", name, file, err)
+			}
+		}
+	}
+	return &Renderer{tmpl: tmpl}, nil
+}
+
+// Render returns the release rendered with the changelog template.
+func (r *Renderer) Render(release *changelog.Release) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.tmpl.Execute(&buf, release); err != nil {
+		return nil, fmt.Errorf("error executing templates: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Funcs returns the functions available to the templates, besides the
+// standard ones, bound to cfg.
+func Funcs(cfg *changelog.Config) template.FuncMap {
+	return template.FuncMap{
+		"sort": func(in []changelog.Note) []changelog.Note {
+			sort.Slice(in, changelog.SortNotes(in))
+			return in
+		},
+		"sortByDate": func(in []changelog.Note) []changelog.Note {
+			sort.Slice(in, func(i, j int) bool {
+				return in[i].Date.Before(in[j].Date)
+			})
+			return in
+		},
+		"combineTypes": func(in ...[]changelog.Note) []changelog.Note {
+			count := 0
+			for _, i := range in {
+				count += len(i)
+			}
+			res := make([]changelog.Note, 0, count)
+			for _, i := range in {
+				res = append(res, i...)
+			}
+			return res
+		},
+		"stringHasPrefix": func(s, prefix string) bool {
+			return strings.HasPrefix(s, prefix)
+		},
+		"issueURL": cfg.IssueURLFor,
+		"typeHeading": func(name string) string {
+			t, _ := cfg.TypeRegistry().Lookup(name)
+			return t.Heading
+		},
+		"sections": func(notesByType map[string][]changelog.Note) []Section {
+			return Sections(cfg.TypeRegistry(), notesByType)
+		},
+	}
+}
+
+// Section is a group of notes displayed under the same heading.
+type Section struct {
+	Heading string
+	Notes   []changelog.Note
+}
+
+// Sections groups notesByType by the headings of their types in reg, in the
+// order of the types. Notes of types without a heading are displayed under
+// the type's name, and notes of unknown types come last.
+func Sections(reg *changelog.TypeRegistry, notesByType map[string][]changelog.Note) []Section {
+	var res []Section
+	index := map[string]int{}
+	add := func(heading string, notes []changelog.Note) {
+		if len(notes) == 0 {
+			return
+		}
+		i, ok := index[heading]
+		if !ok {
+			i = len(res)
+			index[heading] = i
+			res = append(res, Section{Heading: heading})
+		}
+		res[i].Notes = append(res[i].Notes, notes...)
+	}
+	for _, t := range reg.Types() {
+		heading := t.Heading
+		if heading == "" {
+			heading = t.Name
+		}
+		add(heading, notesByType[t.Name])
+	}
+	var unknown []string
+	for typ := range notesByType {
+		if !reg.Valid(typ) {
+			unknown = append(unknown, typ)
+		}
+	}
+	sort.Strings(unknown)
+	for _, typ := range unknown {
+		add(typ, notesByType[typ])
+	}
+	for _, s := range res {
+		sort.Slice(s.Notes, changelog.SortNotes(s.Notes))
+	}
+	return res
+}
@@ -0,0 +1,40 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-changelog"
+)
+
+func TestNewTemplateFileNames(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"changelog.tmpl":    `{{range .Notes}}{{template "release-note.tmpl" .}}{{end}}`,
+		"release-note.tmpl": `{{define "note"}}{{.Body}}{{end}}* {{template "note" .}}` + "\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tmpls, err := LoadTemplates("", filepath.Join(dir, "changelog.tmpl"), filepath.Join(dir, "release-note.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := New(tmpls, &changelog.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.Render(&changelog.Release{Notes: []changelog.Note{{Type: "bug", Body: "Fixed a crash"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "* Fixed a crash\n"; string(got) != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultEntryPattern names entry files after their issue, such as
+// 1234.txt.
+const DefaultEntryPattern = "{issue}"
+
+// entryPlaceholders maps the placeholders of entry patterns to the
+// expressions matching them. The issue stops at the first "-" so that it
+// can be followed by a slug.
+var entryPlaceholders = map[string]string{
+	"{issue}":     `(?P<issue>[^/]+?)`,
+	"{component}": `(?P<component>[^/]+)`,
+	"{slug}":      `(?P<slug>[^/]+)`,
+}
+
+var entryPlaceholderRE = regexp.MustCompile(`\{[^{}]*\}`)
+
+// EntryNaming is the naming scheme of entry files. Its pattern is the path
+// of an entry file relative to the entries directory, in which "{issue}"
+// stands for the issue of the entry, "{component}" for the component its
+// notes are filed under, and "{slug}" for any text:
+//
+//	{component}/{issue}-{slug}
+//
+// The pattern may end with the extension of an entry format, such as
+// {issue}.yaml, in which case entries must be in that format. Otherwise
+// entries may be in any format, and new ones are text.
+type EntryNaming struct {
+	pattern string
+	re      *regexp.Regexp
+
+	// format is the format entries must be in, or empty if any is allowed.
+	format EntryFormat
+
+	// stem is the pattern without its extension.
+	stem string
+}
+
+// DefaultEntryNaming is the naming scheme using DefaultEntryPattern.
+var DefaultEntryNaming = MustEntryNaming(DefaultEntryPattern)
+
+// NewEntryNaming returns the naming scheme of pattern. An error is returned
+// if pattern doesn't contain "{issue}", or contains unknown placeholders.
+func NewEntryNaming(pattern string) (*EntryNaming, error) {
+	if !strings.Contains(pattern, "{issue}") {
+		return nil, fmt.Errorf("entry pattern %q must contain {issue}", pattern)
+	}
+	stem, format := pattern, EntryFormat("")
+	if IsEntryFile(pattern) {
+		stem, format = TrimEntryExtension(pattern), EntryFormatOf(pattern)
+	}
+	var expr strings.Builder
+	expr.WriteString("^")
+	last := 0
+	for _, m := range entryPlaceholderRE.FindAllStringIndex(stem, -1) {
+		placeholder := stem[m[0]:m[1]]
+		sub, ok := entryPlaceholders[placeholder]
+		if !ok {
+			return nil, fmt.Errorf("entry pattern %q: unknown placeholder %s, must be one of {issue}, {component} or {slug}", pattern, placeholder)
+		}
+		if strings.Contains(stem[:m[0]], placeholder) {
+			return nil, fmt.Errorf("entry pattern %q: %s used more than once", pattern, placeholder)
+		}
+		expr.WriteString(regexp.QuoteMeta(stem[last:m[0]]))
+		expr.WriteString(sub)
+		last = m[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(stem[last:]))
+	expr.WriteString("$")
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("entry pattern %q: %w", pattern, err)
+	}
+	return &EntryNaming{pattern: pattern, re: re, format: format, stem: stem}, nil
+}
+
+// MustEntryNaming is like NewEntryNaming, but panics if pattern is invalid.
+func MustEntryNaming(pattern string) *EntryNaming {
+	n, err := NewEntryNaming(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// String returns the pattern of the naming scheme.
+func (n *EntryNaming) String() string {
+	return n.pattern
+}
+
+// Format returns the format of new entry files: the format of the
+// extension of the pattern, or FormatText if it has none.
+func (n *EntryNaming) Format() EntryFormat {
+	if n.format == "" {
+		return FormatText
+	}
+	return n.format
+}
+
+// Uses reports whether the pattern contains placeholder, such as
+// "{component}".
+func (n *EntryNaming) Uses(placeholder string) bool {
+	return strings.Contains(n.stem, placeholder)
+}
+
+// Name returns the path, relative to the entries directory, of a new entry
+// file for issue, filed under component and described by slug, with the
+// extension of Format. An error is returned if a value used by the pattern
+// is empty, or the name doesn't follow the naming scheme, such as when a
+// value contains a slash.
+func (n *EntryNaming) Name(issue, component, slug string) (string, error) {
+	values := map[string]string{"{issue}": issue, "{component}": component, "{slug}": slug}
+	var err error
+	name := entryPlaceholderRE.ReplaceAllStringFunc(n.stem, func(placeholder string) string {
+		if values[placeholder] == "" && err == nil {
+			err = fmt.Errorf("entry pattern %q needs a value for %s", n.pattern, placeholder)
+		}
+		return values[placeholder]
+	})
+	if err != nil {
+		return "", err
+	}
+	if n.format != "" {
+		name += path.Ext(n.pattern)
+	} else {
+		name += ".txt"
+	}
+	if gotIssue, gotComponent, ok := n.Match(name); !ok || gotIssue != issue || n.Uses("{component}") && gotComponent != component {
+		return "", fmt.Errorf("entry file name %s for issue %q doesn't follow the naming scheme %s", name, issue, n.pattern)
+	}
+	return name, nil
+}
+
+// Match reports whether name, the path of an entry file relative to the
+// entries directory, follows the naming scheme, and returns the issue and
+// component it names. When the pattern has an extension, the name must be
+// in its format.
+func (n *EntryNaming) Match(name string) (issue, component string, ok bool) {
+	if n.format != "" && (!IsEntryFile(name) || EntryFormatOf(name) != n.format) {
+		return "", "", false
+	}
+	m := n.re.FindStringSubmatch(TrimEntryExtension(path.Clean(filepath.ToSlash(name))))
+	if m == nil {
+		return "", "", false
+	}
+	for i, group := range n.re.SubexpNames() {
+		switch group {
+		case "issue":
+			issue = m[i]
+		case "component":
+			component = m[i]
+		}
+	}
+	return issue, component, true
+}
+
+// Parse returns the issue and component named by name, the path of an entry
+// file relative to the entries directory. Names that don't follow the
+// naming scheme are named after their issue: the issue is the file name
+// without its directory and extension, and there is no component.
+func (n *EntryNaming) Parse(name string) (issue, component string) {
+	if name == "" {
+		return "", ""
+	}
+	if issue, component, ok := n.Match(name); ok {
+		return issue, component
+	}
+	return TrimEntryExtension(path.Base(filepath.ToSlash(name))), ""
+}
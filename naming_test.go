@@ -0,0 +1,84 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import "testing"
+
+func TestEntryNamingParse(t *testing.T) {
+	cases := []struct {
+		name      string
+		pattern   string
+		file      string
+		issue     string
+		component string
+		match     bool
+	}{
+		{name: "default", pattern: DefaultEntryPattern, file: "1234.txt", issue: "1234", match: true},
+		{name: "default in another format", pattern: DefaultEntryPattern, file: "1234.yaml", issue: "1234", match: true},
+		{name: "default in a directory", pattern: DefaultEntryPattern, file: "storage/1234.txt", issue: "1234"},
+		{name: "slug", pattern: "{issue}-{slug}", file: "1234-fixed-a-crash.txt", issue: "1234", match: true},
+		{name: "missing slug", pattern: "{issue}-{slug}", file: "1234.txt", issue: "1234"},
+		{name: "component", pattern: "{component}/{issue}", file: "storage/1234.txt", issue: "1234", component: "storage", match: true},
+		{name: "missing component", pattern: "{component}/{issue}", file: "1234.txt", issue: "1234"},
+		{name: "nested component", pattern: "{component}/{issue}", file: "a/b/1234.txt", issue: "1234"},
+		{name: "format", pattern: "{issue}.yaml", file: "1234.yaml", issue: "1234", match: true},
+		{name: "other format", pattern: "{issue}.yaml", file: "1234.json", issue: "1234"},
+		{name: "not an entry extension", pattern: DefaultEntryPattern, file: "1234.md", issue: "1234.md", match: true},
+		{name: "not an entry extension with a format", pattern: "{issue}.txt", file: "1234.md", issue: "1234.md"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := MustEntryNaming(tc.pattern)
+			issue, component := n.Parse(tc.file)
+			if issue != tc.issue || component != tc.component {
+				t.Errorf("Parse(%q) = %q, %q, want %q, %q", tc.file, issue, component, tc.issue, tc.component)
+			}
+			if _, _, ok := n.Match(tc.file); ok != tc.match {
+				t.Errorf("Match(%q) = %v, want %v", tc.file, ok, tc.match)
+			}
+		})
+	}
+}
+
+func TestEntryNamingName(t *testing.T) {
+	cases := []struct {
+		name      string
+		pattern   string
+		issue     string
+		component string
+		slug      string
+		want      string
+		wantErr   bool
+	}{
+		{name: "default", pattern: DefaultEntryPattern, issue: "1234", want: "1234.txt"},
+		{name: "unused values", pattern: DefaultEntryPattern, issue: "1234", component: "storage", slug: "fixed", want: "1234.txt"},
+		{name: "slug", pattern: "{issue}-{slug}", issue: "1234", slug: "fixed-a-crash", want: "1234-fixed-a-crash.txt"},
+		{name: "component", pattern: "{component}/{issue}", issue: "1234", component: "storage", want: "storage/1234.txt"},
+		{name: "format", pattern: "{issue}.json", issue: "1234", want: "1234.json"},
+		{name: "missing issue", pattern: DefaultEntryPattern, wantErr: true},
+		{name: "missing component", pattern: "{component}/{issue}", issue: "1234", wantErr: true},
+		{name: "missing slug", pattern: "{issue}-{slug}", issue: "1234", wantErr: true},
+		{name: "slash in the component", pattern: "{component}/{issue}", issue: "1234", component: "a/b", wantErr: true},
+		{name: "slash in the issue", pattern: DefaultEntryPattern, issue: "a/1234", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MustEntryNaming(tc.pattern).Name(tc.issue, tc.component, tc.slug)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Name(%q, %q, %q) returned the error %v, want an error: %v", tc.issue, tc.component, tc.slug, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Name(%q, %q, %q) = %q, want %q", tc.issue, tc.component, tc.slug, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewEntryNaming(t *testing.T) {
+	for _, pattern := range []string{"{slug}", "{issue}-{name}", "{issue}/{issue}"} {
+		if _, err := NewEntryNaming(pattern); err == nil {
+			t.Errorf("NewEntryNaming(%q) returned no error", pattern)
+		}
+	}
+}
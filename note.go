@@ -4,6 +4,7 @@
 package changelog
 
 import (
+	"fmt"
 	"regexp"
 	"sort"
 	"strings"
@@ -16,56 +17,288 @@ type Note struct {
 	Issue string
 	Hash  string
 	Date  time.Time
+
+	// Subcategory is the service or area of the codebase the note is filed
+	// under, taken from the subcategory prefix of Body, if any.
+	Subcategory string
+
+	// Description is Body without its subcategory prefix.
+	Description string
+
+	// Attributes holds the key=value attributes set in the header of the
+	// note's block, after its type. An "issue" attribute also replaces
+	// Issue.
+	Attributes map[string]string
+}
+
+// DefaultSubcategoryPattern matches the "subcategory: " prefix
+// changelog-entry writes at the start of a note body.
+const DefaultSubcategoryPattern = `^([a-z0-9][a-z0-9_./-]*): `
+
+var defaultSubcategoryRE = regexp.MustCompile(DefaultSubcategoryPattern)
+
+// NoteParser parses the notes of entries.
+type NoteParser struct {
+	// Types resolves the aliases of note types. Defaults to DefaultTypes.
+	Types *TypeRegistry
+
+	// SubcategoryPattern matches the subcategory prefix of a note body,
+	// with the subcategory in its first capture group. Defaults to
+	// DefaultSubcategoryPattern.
+	SubcategoryPattern *regexp.Regexp
+
+	// Subcategories lists the subcategories notes may be filed under. If
+	// it is empty, any subcategory is allowed.
+	Subcategories []string
+
+	// Attributes lists the attributes allowed in the headers of release
+	// note blocks. Defaults to DefaultAttributes.
+	Attributes []Attribute
+
+	// Naming extracts the issue and component of entries from the paths
+	// of their files, held by Entry.Issue. If it is nil, Entry.Issue is
+	// used as the issue unchanged.
+	Naming *EntryNaming
+}
+
+// NotesFromEntry returns the notes in entry, with type aliases resolved to
+// their canonical names and subcategories split from their bodies, sorted
+// with SortNotes. If p.Naming is set, the issue of the notes, and their
+// "component" attribute unless it is set, are extracted from the entry's
+// file path by it.
+func (p *NoteParser) NotesFromEntry(entry Entry) []Note {
+	if entry.Format == "" {
+		entry.Format = EntryFormatOf(entry.Issue)
+	}
+	var component string
+	if p.Naming != nil {
+		entry.Issue, component = p.Naming.Parse(entry.Issue)
+	}
+	notes := notesFromEntry(entry)
+	for i := range notes {
+		notes[i].Type = p.types().Canonical(notes[i].Type)
+		p.splitSubcategory(&notes[i])
+		if _, ok := notes[i].Attributes["component"]; component != "" && !ok {
+			if notes[i].Attributes == nil {
+				notes[i].Attributes = map[string]string{}
+			}
+			notes[i].Attributes["component"] = component
+		}
+	}
+	sort.Slice(notes, SortNotes(notes))
+	return notes
+}
+
+// SplitSubcategory returns the subcategory prefix of body, if any, and the
+// rest of body.
+func (p *NoteParser) SplitSubcategory(body string) (subcategory, description string) {
+	re := p.SubcategoryPattern
+	if re == nil {
+		re = defaultSubcategoryRE
+	}
+	m := re.FindStringSubmatchIndex(body)
+	if m == nil || len(m) < 4 || m[0] != 0 || m[2] < 0 {
+		return "", body
+	}
+	return body[m[2]:m[3]], body[m[1]:]
+}
+
+func (p *NoteParser) splitSubcategory(note *Note) {
+	note.Subcategory, note.Description = p.SplitSubcategory(note.Body)
 }
 
-var textInBodyREs = []*regexp.Regexp{
-	regexp.MustCompile("(?ms)^```release-note\r?\n(?P<note>.+?)\r?\n```"),
-	regexp.MustCompile("(?ms)^```releasenote\r?\n(?P<note>.+?)\r?\n```"),
-	regexp.MustCompile("(?ms)^```release-note:(?P<type>[^\r\n]*)\r?\n?(?P<note>.*?)\r?\n?```"),
-	regexp.MustCompile("(?ms)^```releasenote:(?P<type>[^\r\n]*)\r?\n?(?P<note>.*?)\r?\n?```"),
+func (p *NoteParser) types() *TypeRegistry {
+	if p.Types == nil {
+		return defaultTypes()
+	}
+	return p.Types
 }
 
+func (p *NoteParser) attributes() []Attribute {
+	if p.Attributes == nil {
+		return DefaultAttributes
+	}
+	return p.Attributes
+}
+
+// noteFenceKinds are the info strings that open release note blocks. They
+// are followed by a colon and the type of the note, and may be followed by
+// its attributes.
+var noteFenceKinds = []string{"release-note", "releasenote"}
+
+// NotesFromEntry returns the notes in entry, sorted with SortNotes.
 func NotesFromEntry(entry Entry) []Note {
-	var res []Note
-	for _, re := range textInBodyREs {
-		matches := re.FindAllStringSubmatch(entry.Body, -1)
-		if len(matches) == 0 {
+	return defaultTypes().NotesFromEntry(entry)
+}
+
+func notesFromEntry(entry Entry) []Note {
+	blocks := noteBlocks(entry)
+	res := make([]Note, 0, len(blocks))
+	for _, b := range blocks {
+		res = append(res, b.note)
+	}
+	return res
+}
+
+// noteBlock is a release note block in the body of an entry.
+type noteBlock struct {
+	note Note
+
+	// start, typeStart and bodyStart are the byte offsets in the entry
+	// body of the block's opening fence, of its type, and of its note, or
+	// -1 if unknown.
+	start, typeStart, bodyStart int
+
+	// attributes are the attributes in the block's header, in the order
+	// they appear.
+	attributes []headerAttribute
+}
+
+// noteBlocks returns the release note blocks in entry, in the order they
+// appear in its body.
+func noteBlocks(entry Entry) []noteBlock {
+	blocks, _ := scanNoteBlocks(entry)
+	return blocks
+}
+
+// scanNoteBlocks returns the release note blocks in entry, in the order they
+// appear in its body, along with errors for the problems that kept some of
+// them from being read.
+func scanNoteBlocks(entry Entry) ([]noteBlock, Diagnostics) {
+	format := entry.Format
+	if format == "" {
+		format = EntryFormatOf(entry.Issue)
+	}
+	if format == FormatText {
+		return fencedNoteBlocks(entry)
+	}
+	return structuredNoteBlocks(entry, format)
+}
+
+// fencedNoteBlocks returns the release note blocks in entry, a text entry,
+// along with errors for malformed and unterminated release note fences.
+// Unterminated blocks are reported but not returned.
+func fencedNoteBlocks(entry Entry) ([]noteBlock, Diagnostics) {
+	var res []noteBlock
+	var diags Diagnostics
+	problem := func(code EntryErrorCode, offset int, format string, args ...interface{}) {
+		line, col := position(entry.Body, offset)
+		diags = append(diags, Diagnostic{
+			Code:     code,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf(format, args...),
+			Note:     -1,
+			Line:     line,
+			Column:   col,
+		})
+	}
+
+	for _, f := range scanFences(entry.Body) {
+		header, typed, ok := noteFenceHeader(f.info)
+		switch {
+		case f.malformed:
+			if ok {
+				problem(EntryErrorMalformedFence, f.start, "malformed changelog note fence: open the block with a line of three backticks followed by %q, and nothing else", strings.TrimRight(f.info, "`"))
+			}
+			continue
+		case !f.closed:
+			if ok {
+				problem(EntryErrorUnterminatedFence, f.start, "unterminated changelog note: close the block with a line of %s", strings.Repeat(string(f.char), f.length))
+			} else if hidesNotes(f) {
+				problem(EntryErrorUnterminatedFence, f.start, "unterminated code block: the changelog notes after it are part of the code block, close it with a line of %s", strings.Repeat(string(f.char), f.length))
+			}
+			continue
+		case !ok:
 			continue
 		}
 
-		for _, match := range matches {
-			note := ""
-			typ := ""
-			for i, name := range re.SubexpNames() {
-				switch name {
-				case "note":
-					note = match[i]
-				case "type":
-					typ = match[i]
-				}
-				if note != "" && typ != "" {
-					break
-				}
+		b := noteBlock{start: f.start, typeStart: -1, bodyStart: f.end}
+		headerStart := f.infoStart + len(f.info) - len(header)
+		fields := headerFields(header, headerStart)
+		typ := ""
+		if typed && len(fields) > 0 && !strings.Contains(fields[0].text, "=") {
+			typ = fields[0].text
+			b.typeStart = fields[0].offset
+			fields = fields[1:]
+		}
+		for _, field := range fields {
+			b.attributes = append(b.attributes, parseHeaderAttribute(field))
+		}
+
+		lines := make([]string, 0, len(f.lines))
+		for _, l := range f.lines {
+			if b.bodyStart == f.end && strings.TrimSpace(l.text) != "" {
+				b.bodyStart = l.offset + len(l.text) - len(strings.TrimLeft(l.text, " \t"))
 			}
+			lines = append(lines, l.text)
+		}
+		note := strings.TrimSpace(strings.Join(lines, "\n"))
+
+		if note == "" && typ == "" && len(b.attributes) == 0 {
+			continue
+		}
 
-			note = strings.TrimSpace(note)
-			typ = strings.TrimSpace(typ)
+		b.setNote(entry, typ, note)
+		res = append(res, b)
+	}
+	return res, diags
+}
 
-			if note == "" && typ == "" {
-				continue
-			}
+// setNote sets the note of the block from entry, with the given type and
+// body, and the block's attributes.
+func (b *noteBlock) setNote(entry Entry, typ, body string) {
+	b.note = Note{
+		Type:  typ,
+		Body:  body,
+		Issue: entry.Issue,
+		Hash:  entry.Hash,
+		Date:  entry.Date,
+	}
+	for _, a := range b.attributes {
+		if a.malformed {
+			continue
+		}
+		if b.note.Attributes == nil {
+			b.note.Attributes = map[string]string{}
+		}
+		b.note.Attributes[a.key] = a.value
+	}
+	if issue, ok := b.note.Attributes["issue"]; ok {
+		b.note.Issue = issue
+	}
+}
 
-			res = append(res, Note{
-				Type:  typ,
-				Body:  note,
-				Issue: entry.Issue,
-				Hash:  entry.Hash,
-				Date:  entry.Date,
-			})
+// noteFenceHeader reports whether info opens a release note block and, if
+// so, returns the rest of the info string: the type and attributes of the
+// note if typed is set, or only its attributes.
+func noteFenceHeader(info string) (header string, typed, ok bool) {
+	for _, kind := range noteFenceKinds {
+		if !strings.HasPrefix(info, kind) {
+			continue
+		}
+		switch rest := info[len(kind):]; {
+		case rest == "":
+			return "", false, true
+		case rest[0] == ':':
+			return rest[1:], true, true
+		case rest[0] == ' ' || rest[0] == '\t':
+			return rest, false, true
 		}
 	}
-	sort.Slice(res, SortNotes(res))
-	return res
+	return "", false, false
+}
+
+// hidesNotes reports whether the unterminated fence f contains lines that
+// would open release note blocks if it were closed.
+func hidesNotes(f fence) bool {
+	for _, l := range f.lines {
+		for _, inner := range scanFences(l.text) {
+			if _, _, ok := noteFenceHeader(inner.info); ok && !inner.malformed {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func SortNotes(res []Note) func(i, j int) bool {
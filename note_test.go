@@ -0,0 +1,32 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import "testing"
+
+func TestNotesFromEntryIssue(t *testing.T) {
+	entry := Entry{Issue: "1234.txt", Body: "```release-note:bug\nFixed a crash\n```\n"}
+	cases := []struct {
+		name string
+		p    *NoteParser
+		want string
+	}{
+		{name: "no naming", p: &NoteParser{}, want: "1234.txt"},
+		{name: "default naming", p: &NoteParser{Naming: DefaultEntryNaming}, want: "1234"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			notes := tc.p.NotesFromEntry(entry)
+			if len(notes) != 1 {
+				t.Fatalf("got %d notes, want 1", len(notes))
+			}
+			if notes[0].Issue != tc.want {
+				t.Errorf("got issue %q, want %q", notes[0].Issue, tc.want)
+			}
+		})
+	}
+	if notes := NotesFromEntry(entry); len(notes) != 1 || notes[0].Issue != "1234.txt" {
+		t.Errorf("NotesFromEntry(%+v) = %+v, want the issue 1234.txt", entry, notes)
+	}
+}
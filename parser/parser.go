@@ -0,0 +1,208 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package parser reads rendered changelog files, such as the CHANGELOG.md
+// produced by changelog-build, back into releases.
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-changelog"
+)
+
+// Options controls how the notes of a changelog are attributed to types.
+type Options struct {
+	// Headings maps section headings, such as "BUG FIXES", to the type of
+	// the notes under them. Defaults to DefaultHeadings(changelog.DefaultTypes).
+	Headings map[string]string
+
+	// Prefixes maps the bold prefixes some notes are rendered with, such as
+	// "New Resource" in "**New Resource:** `foo`", to the type of those
+	// notes. The prefix is removed from the note body. Defaults to
+	// DefaultPrefixes.
+	Prefixes map[string]string
+
+	// Notes splits the subcategories of the notes. Defaults to a
+	// changelog.NoteParser using changelog.DefaultSubcategoryPattern.
+	Notes *changelog.NoteParser
+}
+
+// DefaultPrefixes are the prefixes the default release note template of
+// changelog-build renders notes with.
+var DefaultPrefixes = map[string]string{
+	"New Resource":           "new-resource",
+	"New Data Source":        "new-datasource",
+	"New Function":           "new-function",
+	"New Ephemeral Resource": "new-ephemeral",
+	"New Action":             "new-action",
+}
+
+// HeadingAliases maps headings other changelogs commonly use to the
+// heading of the same types in changelog.DefaultTypes.
+var HeadingAliases = map[string]string{
+	"ENHANCEMENTS": "IMPROVEMENTS",
+}
+
+// DefaultHeadings maps the heading of each type in reg to the type. When
+// several types share a heading, the first one in reg is used. The headings
+// in HeadingAliases map to the type of the heading they alias, unless reg
+// has a type with that heading.
+func DefaultHeadings(reg *changelog.TypeRegistry) map[string]string {
+	res := map[string]string{}
+	for _, t := range reg.Types() {
+		heading := t.Heading
+		if heading == "" {
+			heading = t.Name
+		}
+		if _, ok := res[heading]; !ok {
+			res[heading] = t.Name
+		}
+	}
+	for alias, heading := range HeadingAliases {
+		if _, ok := res[alias]; ok {
+			continue
+		}
+		if t, ok := res[heading]; ok {
+			res[alias] = t
+		}
+	}
+	return res
+}
+
+// amendedHeading is the heading the default changelog template of
+// changelog-build lists amended notes under.
+const amendedHeading = "AMENDED"
+
+var (
+	// sectionHeadingRE matches the headings notes are grouped under, either
+	// "BUG FIXES:" or a Markdown heading like "### Fixed".
+	sectionHeadingRE = regexp.MustCompile(`^(?:([A-Z][A-Z0-9 ()/&-]*):|#{3,}[ \t]+(.+?))[ \t]*$`)
+
+	// bulletRE matches the first line of a note.
+	bulletRE = regexp.MustCompile(`^[*-][ \t]+(.*)$`)
+
+	// prefixRE matches the bold prefix of a note body.
+	prefixRE = regexp.MustCompile(`^\*\*([^*]+?):?\*\*:?[ \t]*`)
+
+	// issueRefRE matches the references to issues at the end of a note,
+	// such as "([GH-123])", "[GH-123](https://...)", "([#123](https://...))"
+	// and "(#123)".
+	issueRefRE = regexp.MustCompile(`\(?\[(?:GH-|#)([0-9]+)\](?:\([^)\s]*\))?\)?|\(#([0-9]+)\)`)
+
+	// linkDefinitionRE matches Markdown link reference definitions, which
+	// are used to link the issue references.
+	linkDefinitionRE = regexp.MustCompile(`^\[[^\]]+\]:[ \t]`)
+)
+
+// Parse reads the releases in the changelog src. Each version section
+// becomes a Release, in the order they appear, holding the notes listed as
+// bullets in it. Notes listed under "AMENDED:" make up its AmendedNotes.
+//
+// Notes are attributed to a type by the heading they are listed under, and
+// their bold prefix, if any. Notes under a heading that isn't in
+// opts.Headings get the heading as their type. The issue of a note is the
+// first issue it references; it is empty if the note references no issue.
+func Parse(src []byte, opts Options) []changelog.Release {
+	doc := changelog.ParseDocument(src)
+	res := make([]changelog.Release, 0, len(doc.Sections))
+	for _, s := range doc.Sections {
+		rel := ParseSection(s.Body, opts)
+		rel.Version = s.Version
+		rel.Date = parseDate(s.Title)
+		res = append(res, rel)
+	}
+	return res
+}
+
+// ParseSection reads the notes in body, the contents of a single version
+// section without its heading, such as the output of changelog-build. The
+// returned release has no version or date. Notes are attributed as in Parse.
+func ParseSection(body []byte, opts Options) changelog.Release {
+	if opts.Headings == nil {
+		opts.Headings = DefaultHeadings(changelog.DefaultTypes)
+	}
+	if opts.Prefixes == nil {
+		opts.Prefixes = DefaultPrefixes
+	}
+	if opts.Notes == nil {
+		opts.Notes = &changelog.NoteParser{}
+	}
+	notes, amended := parseNotes(string(body), opts)
+	return *changelog.NewReleaseFromNotes(notes, amended)
+}
+
+// parseNotes returns the notes listed in the body of a version section, and
+// the amended notes listed in it separately.
+func parseNotes(body string, opts Options) (notes, amended []changelog.Note) {
+	var heading string
+	var cur []string
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		if heading == amendedHeading {
+			// amended notes aren't rendered with their type
+			amended = append(amended, parseNote("", strings.Join(cur, "\n"), opts))
+		} else {
+			notes = append(notes, parseNote(heading, strings.Join(cur, "\n"), opts))
+		}
+		cur = nil
+	}
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || linkDefinitionRE.MatchString(trimmed):
+			flush()
+		case sectionHeadingRE.MatchString(line):
+			flush()
+			m := sectionHeadingRE.FindStringSubmatch(line)
+			heading = m[1] + m[2]
+		case bulletRE.MatchString(line):
+			flush()
+			cur = []string{bulletRE.FindStringSubmatch(line)[1]}
+		case cur != nil:
+			// a continuation of the current note
+			cur = append(cur, trimmed)
+		}
+	}
+	flush()
+	return notes, amended
+}
+
+// parseNote returns the note rendered as text under heading.
+func parseNote(heading, text string, opts Options) changelog.Note {
+	note := changelog.Note{
+		Type: heading,
+	}
+	if t, ok := opts.Headings[heading]; ok {
+		note.Type = t
+	}
+	if m := prefixRE.FindStringSubmatch(text); m != nil {
+		if t, ok := opts.Prefixes[m[1]]; ok {
+			note.Type = t
+			text = text[len(m[0]):]
+		}
+	}
+	if m := issueRefRE.FindStringSubmatch(text); m != nil {
+		note.Issue = m[1] + m[2]
+	}
+	note.Body = strings.TrimSpace(issueRefRE.ReplaceAllString(text, ""))
+	note.Subcategory, note.Description = opts.Notes.SplitSubcategory(note.Body)
+	return note
+}
+
+// parseDate returns the date in the title of a version heading, such as
+// "(October 18, 2026)" or "- 2026-10-18", or the zero time if it has none.
+func parseDate(title string) time.Time {
+	title = strings.Trim(title, "()- \t")
+	for _, layout := range []string{"January 2, 2006", "2006-01-02", "Jan 2, 2006"} {
+		if t, err := time.Parse(layout, title); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,146 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-changelog"
+)
+
+func TestParse(t *testing.T) {
+	// parsed is the part of a release the tests compare.
+	type parsed struct {
+		version string
+		date    time.Time
+		notes   []changelog.Note
+		amended []changelog.Note
+	}
+	cases := []struct {
+		name string
+		src  string
+		want []parsed
+	}{
+		{
+			name: "changelog-build output",
+			src: `## 1.1.0 (October 18, 2026)
+
+BREAKING CHANGES:
+
+* core: Removed the ` + "`legacy`" + ` flag ([GH-10](https://github.com/o/r/issues/10))
+
+FEATURES:
+
+* **New Resource:** ` + "`foo_bar`" + ` ([GH-11](https://github.com/o/r/issues/11))
+
+IMPROVEMENTS:
+
+* Added a flag ([GH-12](https://github.com/o/r/issues/12))
+
+BUG FIXES:
+
+* storage: Fixed a crash
+when the bucket is empty ([GH-13](https://github.com/o/r/issues/13))
+
+AMENDED:
+
+* Fixed a panic ([GH-9](https://github.com/o/r/issues/9))
+
+## 1.0.0 (January 2, 2026)
+
+NOTES:
+
+* Initial release
+`,
+			want: []parsed{
+				{
+					version: "1.1.0",
+					date:    time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+					notes: []changelog.Note{
+						{Type: "breaking-change", Issue: "10", Body: "core: Removed the `legacy` flag", Subcategory: "core", Description: "Removed the `legacy` flag"},
+						{Type: "bug", Issue: "13", Body: "storage: Fixed a crash\nwhen the bucket is empty", Subcategory: "storage", Description: "Fixed a crash\nwhen the bucket is empty"},
+						{Type: "enhancement", Issue: "12", Body: "Added a flag", Description: "Added a flag"},
+						{Type: "new-resource", Issue: "11", Body: "`foo_bar`", Description: "`foo_bar`"},
+					},
+					amended: []changelog.Note{
+						{Issue: "9", Body: "Fixed a panic", Description: "Fixed a panic"},
+					},
+				},
+				{
+					version: "1.0.0",
+					date:    time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+					notes: []changelog.Note{
+						{Type: "note", Body: "Initial release", Description: "Initial release"},
+					},
+				},
+			},
+		},
+		{
+			name: "heading aliases and markdown headings",
+			src: `## [2.0.0] - 2026-03-04
+
+ENHANCEMENTS:
+
+- Added a flag (#20)
+
+### Fixed
+
+- Fixed a crash ([#21](https://github.com/o/r/pull/21))
+`,
+			want: []parsed{
+				{
+					version: "2.0.0",
+					date:    time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+					notes: []changelog.Note{
+						{Type: "Fixed", Issue: "21", Body: "Fixed a crash", Description: "Fixed a crash"},
+						{Type: "enhancement", Issue: "20", Body: "Added a flag", Description: "Added a flag"},
+					},
+				},
+			},
+		},
+		{
+			name: "no sections",
+			src:  "# Changelog\n\nNothing yet.\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []parsed
+			for _, rel := range Parse([]byte(tc.src), Options{}) {
+				got = append(got, parsed{
+					version: rel.Version,
+					date:    rel.Date,
+					notes:   rel.Notes,
+					amended: rel.AmendedNotes,
+				})
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Parse() =\n%+v\nwant\n%+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDefaultHeadings(t *testing.T) {
+	cases := []struct {
+		name    string
+		heading string
+		want    string
+	}{
+		{name: "heading", heading: "BUG FIXES", want: "bug"},
+		{name: "shared heading", heading: "FEATURES", want: "feature"},
+		{name: "alias", heading: "ENHANCEMENTS", want: "enhancement"},
+		{name: "unknown heading", heading: "CHANGES"},
+	}
+	headings := DefaultHeadings(changelog.DefaultTypes)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := headings[tc.heading]; got != tc.want {
+				t.Errorf("DefaultHeadings()[%q] = %q, want %q", tc.heading, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ReleaseSchemaVersion is the version of the JSON representation of a
+// Release. It is incremented whenever a field is removed or changes meaning;
+// new fields may be added without changing it.
+const ReleaseSchemaVersion = 1
+
+// Release holds the notes that make up a release.
+type Release struct {
+	// Version is the version being released, if known.
+	Version string
+
+	// Date is the date of the release, if known.
+	Date time.Time
+
+	// Notes holds the notes of the entries added in the release, sorted with
+	// SortNotes.
+	Notes []Note
+
+	// NotesByType holds the same notes as Notes, grouped by their type.
+	NotesByType map[string][]Note
+
+	// NotesBySubcategory holds the same notes as Notes, grouped by their
+	// subcategory. Notes without a subcategory are grouped under "".
+	NotesBySubcategory map[string][]Note
+
+	// NotesByTypeAndSubcategory holds the same notes as Notes, grouped by
+	// their type, then by their subcategory.
+	NotesByTypeAndSubcategory map[string]map[string][]Note
+
+	// AmendedNotes holds the notes of entries from earlier releases that
+	// were modified in this release.
+	AmendedNotes []Note
+}
+
+// NewRelease returns a Release holding the notes of entries, parsed with
+// reg. Added entries, and entries that didn't come from a diff, make up the
+// release's Notes. Modified entries are only included, as AmendedNotes, if
+// amended is true. Removed entries are ignored.
+//
+// The issue of each note is the name of its entry file without its
+// extension, and the format of the entry is selected by that extension.
+func NewRelease(entries *EntryList, reg *TypeRegistry, amended bool) *Release {
+	return NewReleaseWith(entries, &NoteParser{Types: reg}, amended)
+}
+
+// NewReleaseWith is like NewRelease, but parses the notes of entries with p,
+// extracting their issues with p.Naming.
+func NewReleaseWith(entries *EntryList, p *NoteParser, amended bool) *Release {
+	var notes, amendedNotes []Note
+	for i := 0; i < entries.Len(); i++ {
+		entry := *entries.Get(i)
+		switch entry.Status {
+		case EntryAdded, "":
+			notes = append(notes, p.NotesFromEntry(entry)...)
+		case EntryModified:
+			if amended {
+				amendedNotes = append(amendedNotes, p.NotesFromEntry(entry)...)
+			}
+		}
+	}
+	return NewReleaseFromNotes(notes, amendedNotes)
+}
+
+// NewReleaseFromNotes returns a Release holding notes, grouped by type and
+// subcategory, and amended, sorted with SortNotes.
+func NewReleaseFromNotes(notes, amended []Note) *Release {
+	rel := &Release{
+		Notes:                     notes,
+		NotesByType:               map[string][]Note{},
+		NotesBySubcategory:        map[string][]Note{},
+		NotesByTypeAndSubcategory: map[string]map[string][]Note{},
+		AmendedNotes:              amended,
+	}
+	sort.Slice(rel.Notes, SortNotes(rel.Notes))
+	sort.Slice(rel.AmendedNotes, SortNotes(rel.AmendedNotes))
+	for _, note := range rel.Notes {
+		rel.NotesByType[note.Type] = append(rel.NotesByType[note.Type], note)
+		rel.NotesBySubcategory[note.Subcategory] = append(rel.NotesBySubcategory[note.Subcategory], note)
+		bySubcategory, ok := rel.NotesByTypeAndSubcategory[note.Type]
+		if !ok {
+			bySubcategory = map[string][]Note{}
+			rel.NotesByTypeAndSubcategory[note.Type] = bySubcategory
+		}
+		bySubcategory[note.Subcategory] = append(bySubcategory[note.Subcategory], note)
+	}
+	return rel
+}
+
+type releaseJSON struct {
+	SchemaVersion int                   `json:"schema_version"`
+	Version       string                `json:"version,omitempty"`
+	Date          *time.Time            `json:"date,omitempty"`
+	Notes         []noteJSON            `json:"notes"`
+	NotesByType   map[string][]noteJSON `json:"notes_by_type"`
+	AmendedNotes  []noteJSON            `json:"amended_notes,omitempty"`
+}
+
+type noteJSON struct {
+	Type        string            `json:"type"`
+	Body        string            `json:"body"`
+	Issue       string            `json:"issue"`
+	Hash        string            `json:"hash,omitempty"`
+	Date        time.Time         `json:"date"`
+	Subcategory string            `json:"subcategory,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+}
+
+func notesToJSON(notes []Note) []noteJSON {
+	res := make([]noteJSON, 0, len(notes))
+	for _, n := range notes {
+		res = append(res, noteJSON{
+			Type:        n.Type,
+			Body:        n.Body,
+			Issue:       n.Issue,
+			Hash:        n.Hash,
+			Date:        n.Date,
+			Subcategory: n.Subcategory,
+			Description: n.Description,
+			Attributes:  n.Attributes,
+		})
+	}
+	return res
+}
+
+func notesFromJSON(notes []noteJSON) []Note {
+	if notes == nil {
+		return nil
+	}
+	res := make([]Note, 0, len(notes))
+	for _, n := range notes {
+		res = append(res, Note{
+			Type:        n.Type,
+			Body:        n.Body,
+			Issue:       n.Issue,
+			Hash:        n.Hash,
+			Date:        n.Date,
+			Subcategory: n.Subcategory,
+			Description: n.Description,
+			Attributes:  n.Attributes,
+		})
+	}
+	return res
+}
+
+// MarshalJSON encodes the release as a JSON object, in the format described
+// in cmd/changelog-build/README.md.
+func (r *Release) MarshalJSON() ([]byte, error) {
+	out := releaseJSON{
+		SchemaVersion: ReleaseSchemaVersion,
+		Version:       r.Version,
+		Notes:         notesToJSON(r.Notes),
+		NotesByType:   make(map[string][]noteJSON, len(r.NotesByType)),
+		AmendedNotes:  notesToJSON(r.AmendedNotes),
+	}
+	if !r.Date.IsZero() {
+		out.Date = &r.Date
+	}
+	for typ, notes := range r.NotesByType {
+		out.NotesByType[typ] = notesToJSON(notes)
+	}
+	if len(out.AmendedNotes) == 0 {
+		out.AmendedNotes = nil
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a release encoded by MarshalJSON. An error is
+// returned if it was encoded with a newer schema version.
+func (r *Release) UnmarshalJSON(b []byte) error {
+	var in releaseJSON
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	if in.SchemaVersion > ReleaseSchemaVersion {
+		return fmt.Errorf("unsupported release schema version %d", in.SchemaVersion)
+	}
+	*r = *NewReleaseFromNotes(notesFromJSON(in.Notes), notesFromJSON(in.AmendedNotes))
+	r.Version = in.Version
+	if in.Date != nil {
+		r.Date = *in.Date
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rule is a lint rule checking the style of a changelog note.
+type Rule interface {
+	// Name identifies the rule in config files and diagnostics.
+	Name() string
+
+	// Check returns a message describing each problem with note, or nil if
+	// the note follows the rule.
+	Check(note Note) []string
+}
+
+// RuleOff disables a rule when used as its severity.
+const RuleOff DiagnosticSeverity = "off"
+
+// DefaultMaxNoteLength is the length limit of the max-length rule when no
+// other limit is configured.
+const DefaultMaxNoteLength = 200
+
+// RuleConfig enables and configures a built-in rule.
+type RuleConfig struct {
+	Name string `hcl:"name,label" yaml:"name"`
+
+	// Severity is "error" (the default), "warning", or "off" to disable the
+	// rule.
+	Severity string `hcl:"severity,optional" yaml:"severity"`
+
+	// Max is the length limit of the max-length rule. Defaults to
+	// DefaultMaxNoteLength.
+	Max int `hcl:"max,optional" yaml:"max"`
+
+	// Phrases are the phrases rejected by the forbidden-phrases rule,
+	// matched regardless of case. Defaults to "this PR".
+	Phrases []string `hcl:"phrases,optional" yaml:"phrases"`
+}
+
+// builtinRules maps the name of each built-in rule to its constructor.
+var builtinRules = map[string]func(RuleConfig) Rule{
+	"subcategory-prefix": func(RuleConfig) Rule { return subcategoryPrefixRule{} },
+	"capitalized":        func(RuleConfig) Rule { return capitalizedRule{} },
+	"no-trailing-period": func(RuleConfig) Rule { return noTrailingPeriodRule{} },
+	"max-length": func(c RuleConfig) Rule {
+		if c.Max <= 0 {
+			c.Max = DefaultMaxNoteLength
+		}
+		return maxLengthRule{max: c.Max}
+	},
+	"forbidden-phrases": func(c RuleConfig) Rule {
+		if len(c.Phrases) == 0 {
+			c.Phrases = []string{"this PR"}
+		}
+		return forbiddenPhrasesRule{phrases: c.Phrases}
+	},
+}
+
+// RuleNames returns the names of the built-in rules, sorted.
+func RuleNames() []string {
+	names := make([]string, 0, len(builtinRules))
+	for name := range builtinRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewRule returns the built-in rule configured by c.
+func NewRule(c RuleConfig) (Rule, error) {
+	newRule, ok := builtinRules[c.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown rule %q, must be one of %v", c.Name, RuleNames())
+	}
+	return newRule(c), nil
+}
+
+// ParseRuleSeverity parses the severity of a rule: "error", "warning" or
+// "off". An empty string is "error".
+func ParseRuleSeverity(s string) (DiagnosticSeverity, error) {
+	switch sev := DiagnosticSeverity(s); sev {
+	case "":
+		return SeverityError, nil
+	case SeverityError, SeverityWarning, RuleOff:
+		return sev, nil
+	default:
+		return "", fmt.Errorf("unknown rule severity %q, must be one of [error warning off]", s)
+	}
+}
+
+// Linter checks the notes of entries against a set of rules.
+type Linter struct {
+	// Notes splits the subcategories of the notes checked. Defaults to a
+	// NoteParser using DefaultSubcategoryPattern.
+	Notes *NoteParser
+
+	rules      []Rule
+	severities []DiagnosticSeverity
+}
+
+// NewLinter returns a Linter for the built-in rules configured by rules.
+// When a rule is configured more than once, the last configuration is used.
+func NewLinter(rules []*RuleConfig) (*Linter, error) {
+	l := &Linter{}
+	index := map[string]int{}
+	for _, c := range rules {
+		sev, err := ParseRuleSeverity(c.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", c.Name, err)
+		}
+		rule, err := NewRule(*c)
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := index[c.Name]; ok {
+			l.rules[i], l.severities[i] = rule, sev
+			continue
+		}
+		index[c.Name] = len(l.rules)
+		l.Add(rule, sev)
+	}
+	return l, nil
+}
+
+// Add adds rule to the linter with the given severity. Rules added with
+// RuleOff are never checked.
+func (l *Linter) Add(rule Rule, severity DiagnosticSeverity) {
+	l.rules = append(l.rules, rule)
+	l.severities = append(l.severities, severity)
+}
+
+// Lint checks every note in the entry against the linter's rules. Each
+// problem found is reported at the start of the note's body, with the
+// EntryErrorRule code and the severity of its rule.
+func (l *Linter) Lint(e *Entry) Diagnostics {
+	var res Diagnostics
+	p := l.Notes
+	if p == nil {
+		p = &NoteParser{}
+	}
+	for i, b := range noteBlocks(*e) {
+		p.splitSubcategory(&b.note)
+		line, col := position(e.Body, b.bodyStart)
+		for j, rule := range l.rules {
+			if l.severities[j] == RuleOff {
+				continue
+			}
+			for _, msg := range rule.Check(b.note) {
+				res = append(res, Diagnostic{
+					Code:     EntryErrorRule,
+					Severity: l.severities[j],
+					Message:  fmt.Sprintf("%s (%s)", msg, rule.Name()),
+					Rule:     rule.Name(),
+					Note:     i,
+					Line:     line,
+					Column:   col,
+				})
+			}
+		}
+	}
+	return res
+}
+
+// Linter returns a Linter for the rules enabled in the config.
+func (c *Config) Linter() (*Linter, error) {
+	l, err := NewLinter(c.Rules)
+	if err != nil {
+		return nil, err
+	}
+	l.Notes = c.NoteParser()
+	return l, nil
+}
+
+type subcategoryPrefixRule struct{}
+
+func (subcategoryPrefixRule) Name() string { return "subcategory-prefix" }
+
+func (subcategoryPrefixRule) Check(note Note) []string {
+	if note.Subcategory == "" {
+		return []string{`note must start with a "subcategory: " prefix`}
+	}
+	return nil
+}
+
+type capitalizedRule struct{}
+
+func (capitalizedRule) Name() string { return "capitalized" }
+
+func (capitalizedRule) Check(note Note) []string {
+	r, _ := utf8.DecodeRuneInString(note.Description)
+	if r != '`' && !unicode.IsUpper(r) {
+		return []string{"note must begin with a capital letter or a backticked identifier"}
+	}
+	return nil
+}
+
+type noTrailingPeriodRule struct{}
+
+func (noTrailingPeriodRule) Name() string { return "no-trailing-period" }
+
+func (noTrailingPeriodRule) Check(note Note) []string {
+	if strings.HasSuffix(strings.TrimSpace(note.Body), ".") {
+		return []string{"note must not end with a period"}
+	}
+	return nil
+}
+
+type maxLengthRule struct {
+	max int
+}
+
+func (maxLengthRule) Name() string { return "max-length" }
+
+func (r maxLengthRule) Check(note Note) []string {
+	if n := utf8.RuneCountInString(note.Body); n > r.max {
+		return []string{fmt.Sprintf("note is %d characters long, more than the limit of %d", n, r.max)}
+	}
+	return nil
+}
+
+type forbiddenPhrasesRule struct {
+	phrases []string
+}
+
+func (forbiddenPhrasesRule) Name() string { return "forbidden-phrases" }
+
+func (r forbiddenPhrasesRule) Check(note Note) []string {
+	var res []string
+	body := strings.ToLower(note.Body)
+	for _, p := range r.phrases {
+		if strings.Contains(body, strings.ToLower(p)) {
+			res = append(res, fmt.Sprintf("note must not contain %q", p))
+		}
+	}
+	return res
+}
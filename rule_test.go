@@ -0,0 +1,101 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	cases := []struct {
+		name string
+		rule RuleConfig
+		body string
+		want []string
+	}{
+		{name: "subcategory prefix", rule: RuleConfig{Name: "subcategory-prefix"}, body: "storage: Fixed a crash"},
+		{name: "no subcategory prefix", rule: RuleConfig{Name: "subcategory-prefix"}, body: "Fixed a crash", want: []string{`note must start with a "subcategory: " prefix`}},
+		{name: "capitalized", rule: RuleConfig{Name: "capitalized"}, body: "storage: Fixed a crash"},
+		{name: "backticked identifier", rule: RuleConfig{Name: "capitalized"}, body: "`foo_bar`: fixed a crash"},
+		{name: "not capitalized", rule: RuleConfig{Name: "capitalized"}, body: "storage: fixed a crash", want: []string{"note must begin with a capital letter or a backticked identifier"}},
+		{name: "no trailing period", rule: RuleConfig{Name: "no-trailing-period"}, body: "Fixed a crash"},
+		{name: "trailing period", rule: RuleConfig{Name: "no-trailing-period"}, body: "Fixed a crash.\n", want: []string{"note must not end with a period"}},
+		{name: "default max length", rule: RuleConfig{Name: "max-length"}, body: strings.Repeat("a", DefaultMaxNoteLength)},
+		{name: "over the default max length", rule: RuleConfig{Name: "max-length"}, body: strings.Repeat("a", DefaultMaxNoteLength+1), want: []string{"note is 201 characters long, more than the limit of 200"}},
+		{name: "configured max length", rule: RuleConfig{Name: "max-length", Max: 10}, body: "Fixed a crash", want: []string{"note is 13 characters long, more than the limit of 10"}},
+		{name: "max length in characters", rule: RuleConfig{Name: "max-length", Max: 5}, body: "héllo"},
+		{name: "default forbidden phrase", rule: RuleConfig{Name: "forbidden-phrases"}, body: "Fixed a crash in This PR", want: []string{`note must not contain "this PR"`}},
+		{name: "configured forbidden phrases", rule: RuleConfig{Name: "forbidden-phrases", Phrases: []string{"TODO", "WIP"}}, body: "wip: todo", want: []string{`note must not contain "TODO"`, `note must not contain "WIP"`}},
+		{name: "no forbidden phrases", rule: RuleConfig{Name: "forbidden-phrases"}, body: "Fixed a crash"},
+	}
+	p := &NoteParser{}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := NewRule(tc.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rule.Name() != tc.rule.Name {
+				t.Errorf("got the rule %q, want %q", rule.Name(), tc.rule.Name)
+			}
+			note := Note{Type: "bug", Body: tc.body}
+			p.splitSubcategory(&note)
+			if got := rule.Check(note); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Check(%q) = %q, want %q", tc.body, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewLinter(t *testing.T) {
+	body := "```release-note:bug\nfixed a crash.\n```\n"
+	cases := []struct {
+		name    string
+		rules   []*RuleConfig
+		want    []string
+		wantErr bool
+	}{
+		{name: "no rules"},
+		{
+			name:  "default severity",
+			rules: []*RuleConfig{{Name: "capitalized"}},
+			want:  []string{"2:1: error: note must begin with a capital letter or a backticked identifier (capitalized)"},
+		},
+		{
+			name:  "severities",
+			rules: []*RuleConfig{{Name: "capitalized", Severity: "warning"}, {Name: "no-trailing-period", Severity: "off"}},
+			want:  []string{"2:1: warning: note must begin with a capital letter or a backticked identifier (capitalized)"},
+		},
+		{
+			name:  "last configuration",
+			rules: []*RuleConfig{{Name: "max-length", Max: 5, Severity: "warning"}, {Name: "no-trailing-period"}, {Name: "max-length", Max: 10}},
+			want: []string{
+				"2:1: error: note is 14 characters long, more than the limit of 10 (max-length)",
+				"2:1: error: note must not end with a period (no-trailing-period)",
+			},
+		},
+		{name: "unknown rule", rules: []*RuleConfig{{Name: "spelling"}}, wantErr: true},
+		{name: "unknown severity", rules: []*RuleConfig{{Name: "capitalized", Severity: "fatal"}}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := NewLinter(tc.rules)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewLinter() returned the error %v, want an error: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, d := range l.Lint(&Entry{Body: body}) {
+				got = append(got, d.String())
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Lint() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/mod/semver"
+)
+
+// DefaultTagPattern is the pattern release tags are matched against when no
+// other pattern is configured.
+const DefaultTagPattern = "v*"
+
+// ErrNoPreviousRelease is returned by PreviousRelease when no release tag is
+// reachable from the given ref.
+var ErrNoPreviousRelease = errors.New("no previous release found")
+
+// ReleaseTagOptions controls which tags are considered releases.
+type ReleaseTagOptions struct {
+	// Pattern is the glob, in path.Match syntax, that release tag names
+	// must match. Defaults to DefaultTagPattern.
+	Pattern string
+
+	// IncludePrereleases allows tags with a prerelease version, such as
+	// v1.2.0-beta1, to be chosen.
+	IncludePrereleases bool
+}
+
+// TagVersion returns the semantic version of the release tag name, in the
+// canonical form used by golang.org/x/mod/semver, or an empty string if
+// name isn't a valid version. Anything up to the last "/" in name is
+// ignored, so "sdk/v1.2.0" is version "v1.2.0", and the leading "v" is
+// optional.
+func TagVersion(name string) string {
+	v := name[strings.LastIndex(name, "/")+1:]
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	if !semver.IsValid(v) {
+		return ""
+	}
+	return semver.Canonical(v)
+}
+
+// PreviousRelease returns the name of the release tag preceding ref in r:
+// the release tag with the highest version among the strict ancestors of
+// ref. If ref is itself tagged as a release, only lower versions are
+// considered. ErrNoPreviousRelease is returned if there is no such tag.
+func PreviousRelease(r *git.Repository, ref string, opts ReleaseTagOptions) (string, error) {
+	pattern := opts.Pattern
+	if pattern == "" {
+		pattern = DefaultTagPattern
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return "", fmt.Errorf("invalid tag pattern %q: %w", pattern, err)
+	}
+	hash, err := r.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", fmt.Errorf("could not resolve revision %s: %w", ref, err)
+	}
+
+	// find the commits of all release tags
+	type release struct {
+		tag, version string
+	}
+	releases := map[plumbing.Hash][]release{}
+	tags, err := r.Tags()
+	if err != nil {
+		return "", err
+	}
+	err = tags.ForEach(func(t *plumbing.Reference) error {
+		name := t.Name().Short()
+		if ok, _ := path.Match(pattern, name); !ok {
+			return nil
+		}
+		v := TagVersion(name)
+		if v == "" || (semver.Prerelease(v) != "" && !opts.IncludePrereleases) {
+			return nil
+		}
+		commit, err := tagCommit(r, t)
+		if err != nil {
+			return fmt.Errorf("could not resolve tag %s: %w", name, err)
+		}
+		if commit != nil {
+			releases[*commit] = append(releases[*commit], release{tag: name, version: v})
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// releases on ref itself bound the versions that may be chosen
+	var ceiling string
+	for _, rel := range releases[*hash] {
+		if ceiling == "" || semver.Compare(rel.version, ceiling) < 0 {
+			ceiling = rel.version
+		}
+	}
+
+	start, err := r.CommitObject(*hash)
+	if err != nil {
+		return "", err
+	}
+	missing, err := shallowParents(r)
+	if err != nil {
+		return "", err
+	}
+	var best *release
+	err = object.NewCommitPreorderIter(start, missing, nil).ForEach(func(c *object.Commit) error {
+		if c.Hash == *hash {
+			return nil
+		}
+		for _, rel := range releases[c.Hash] {
+			if ceiling != "" && semver.Compare(rel.version, ceiling) >= 0 {
+				continue
+			}
+			if best == nil || semver.Compare(rel.version, best.version) > 0 {
+				rel := rel
+				best = &rel
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if best == nil {
+		return "", ErrNoPreviousRelease
+	}
+	return best.tag, nil
+}
+
+// ReleaseTag returns the name of the release tag in r matching opts.Pattern
+// for version, or an empty string if there is none. Versions are compared
+// as semantic versions, so "1.2.0" matches a "v1.2.0" tag.
+// opts.IncludePrereleases is ignored.
+func ReleaseTag(r *git.Repository, version string, opts ReleaseTagOptions) (string, error) {
+	pattern := opts.Pattern
+	if pattern == "" {
+		pattern = DefaultTagPattern
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return "", fmt.Errorf("invalid tag pattern %q: %w", pattern, err)
+	}
+	want := TagVersion(version)
+	if want == "" {
+		return "", nil
+	}
+	tags, err := r.Tags()
+	if err != nil {
+		return "", err
+	}
+	var res string
+	err = tags.ForEach(func(t *plumbing.Reference) error {
+		name := t.Name().Short()
+		if ok, _ := path.Match(pattern, name); ok && res == "" && TagVersion(name) == want {
+			res = name
+		}
+		return nil
+	})
+	return res, err
+}
+
+// ReleaseTagAt returns the name of the release tag in r matching opts that
+// points at ref, or an empty string if there is none. If several do, the
+// one with the highest version is returned.
+func ReleaseTagAt(r *git.Repository, ref string, opts ReleaseTagOptions) (string, error) {
+	pattern := opts.Pattern
+	if pattern == "" {
+		pattern = DefaultTagPattern
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return "", fmt.Errorf("invalid tag pattern %q: %w", pattern, err)
+	}
+	hash, err := r.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", fmt.Errorf("could not resolve revision %s: %w", ref, err)
+	}
+	tags, err := r.Tags()
+	if err != nil {
+		return "", err
+	}
+	var res, resVersion string
+	err = tags.ForEach(func(t *plumbing.Reference) error {
+		name := t.Name().Short()
+		if ok, _ := path.Match(pattern, name); !ok {
+			return nil
+		}
+		v := TagVersion(name)
+		if v == "" || (semver.Prerelease(v) != "" && !opts.IncludePrereleases) {
+			return nil
+		}
+		commit, err := tagCommit(r, t)
+		if err != nil {
+			return fmt.Errorf("could not resolve tag %s: %w", name, err)
+		}
+		if commit != nil && *commit == *hash && (res == "" || semver.Compare(v, resVersion) > 0) {
+			res, resVersion = name, v
+		}
+		return nil
+	})
+	return res, err
+}
+
+// PreviousReleaseLocal is like PreviousRelease, but operates on the local git
+// repository at repoPath.
+func PreviousReleaseLocal(repoPath, ref string, opts ReleaseTagOptions) (string, error) {
+	r, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("error opening repository at %q: %w", repoPath, err)
+	}
+	return PreviousRelease(r, ref, opts)
+}
+
+// tagCommit returns the hash of the commit the tag t points at, peeling
+// annotated tags. If t doesn't point at a commit, nil is returned.
+func tagCommit(r *git.Repository, t *plumbing.Reference) (*plumbing.Hash, error) {
+	hash := t.Hash()
+	tag, err := r.TagObject(hash)
+	switch {
+	case errors.Is(err, plumbing.ErrObjectNotFound):
+		// a lightweight tag
+		return &hash, nil
+	case err != nil:
+		return nil, err
+	}
+	commit, err := tag.Commit()
+	if errors.Is(err, object.ErrUnsupportedObject) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &commit.Hash, nil
+}
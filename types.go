@@ -0,0 +1,196 @@
+// Copyright IBM Corp. 2020, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package changelog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Type describes a changelog entry type.
+type Type struct {
+	// Name is the canonical name of the type, as used in release-note
+	// blocks.
+	Name string
+
+	// Aliases are alternative names that are accepted for the type and
+	// resolved to Name when parsing notes.
+	Aliases []string
+
+	// Heading is the section heading notes of this type are displayed
+	// under.
+	Heading string
+
+	// Order controls the position of the type relative to the others in the
+	// registry; lower values come first.
+	Order int
+
+	// Deprecated types are still accepted, but should no longer be offered
+	// for new entries.
+	Deprecated bool
+}
+
+// TypeRegistry is the set of changelog entry types allowed in a repository.
+// It is safe for concurrent use once created.
+type TypeRegistry struct {
+	types  []Type
+	byName map[string]int
+}
+
+// DefaultTypes is the registry used when a repository does not configure its
+// own types. It holds the types in TypeValues, as declared; the functions
+// falling back to DefaultTypes use the types in TypeValues instead if it
+// was changed.
+var DefaultTypes = MustTypeRegistry(
+	Type{Name: "note", Heading: "NOTES", Order: 0},
+	Type{Name: "deprecation", Heading: "DEPRECATIONS", Order: 1},
+	Type{Name: "breaking-change", Heading: "BREAKING CHANGES", Order: 2},
+	Type{Name: "feature", Heading: "FEATURES", Order: 3},
+	Type{Name: "new-resource", Heading: "FEATURES", Order: 3},
+	Type{Name: "new-datasource", Heading: "FEATURES", Order: 3},
+	Type{Name: "new-ephemeral", Heading: "FEATURES", Order: 3},
+	Type{Name: "new-function", Heading: "FEATURES", Order: 3},
+	Type{Name: "new-action", Heading: "FEATURES", Order: 3},
+	Type{Name: "enhancement", Heading: "IMPROVEMENTS", Order: 4},
+	Type{Name: "improvement", Heading: "IMPROVEMENTS", Order: 4},
+	Type{Name: "bug", Heading: "BUG FIXES", Order: 5},
+)
+
+// defaultTypeValues is TypeValues as declared, to detect types added to it.
+var defaultTypeValues = strings.Join(TypeValues, "\n")
+
+var typeValuesCache struct {
+	sync.Mutex
+	values string
+	types  *TypeRegistry
+}
+
+// defaultTypes returns the registry used when no types are configured:
+// DefaultTypes, unless TypeValues was changed, in which case it holds the
+// types in TypeValues for compatibility with programs adding their own
+// types to it. Types missing from DefaultTypes come after the others.
+func defaultTypes() *TypeRegistry {
+	values := strings.Join(TypeValues, "\n")
+	if values == defaultTypeValues {
+		return DefaultTypes
+	}
+	typeValuesCache.Lock()
+	defer typeValuesCache.Unlock()
+	if typeValuesCache.types != nil && typeValuesCache.values == values {
+		return typeValuesCache.types
+	}
+	last := 0
+	for _, t := range DefaultTypes.types {
+		if t.Order > last {
+			last = t.Order
+		}
+	}
+	types := make([]Type, 0, len(TypeValues))
+	seen := make(map[string]bool, len(TypeValues))
+	for _, name := range TypeValues {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		t, ok := DefaultTypes.Lookup(name)
+		if !ok || t.Name != name {
+			t = Type{Name: name, Order: last + 1}
+		}
+		types = append(types, t)
+	}
+	reg, err := NewTypeRegistry(types...)
+	if err != nil {
+		// names are unique and aliases dropped, so this can't happen
+		panic(err)
+	}
+	typeValuesCache.values, typeValuesCache.types = values, reg
+	return reg
+}
+
+// NewTypeRegistry returns a TypeRegistry holding types, sorted by their
+// Order. Types with the same Order keep the order they were passed in. An
+// error is returned if a name or alias is used more than once.
+func NewTypeRegistry(types ...Type) (*TypeRegistry, error) {
+	r := &TypeRegistry{
+		types:  make([]Type, len(types)),
+		byName: make(map[string]int, len(types)),
+	}
+	copy(r.types, types)
+	sort.SliceStable(r.types, func(i, j int) bool {
+		return r.types[i].Order < r.types[j].Order
+	})
+	for i, t := range r.types {
+		if t.Name == "" {
+			return nil, fmt.Errorf("changelog type %d has no name", i)
+		}
+		for _, name := range append([]string{t.Name}, t.Aliases...) {
+			if _, ok := r.byName[name]; ok {
+				return nil, fmt.Errorf("changelog type %q declared more than once", name)
+			}
+			r.byName[name] = i
+		}
+	}
+	return r, nil
+}
+
+// MustTypeRegistry is like NewTypeRegistry, but panics on error.
+func MustTypeRegistry(types ...Type) *TypeRegistry {
+	r, err := NewTypeRegistry(types...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Lookup returns the type with the given name or alias.
+func (r *TypeRegistry) Lookup(name string) (Type, bool) {
+	i, ok := r.byName[name]
+	if !ok {
+		return Type{}, false
+	}
+	return r.types[i], true
+}
+
+// Valid reports whether name is the name or alias of a type in the registry.
+func (r *TypeRegistry) Valid(name string) bool {
+	_, ok := r.byName[name]
+	return ok
+}
+
+// Canonical returns the canonical name for name, resolving aliases. Unknown
+// names are returned unchanged.
+func (r *TypeRegistry) Canonical(name string) string {
+	if t, ok := r.Lookup(name); ok {
+		return t.Name
+	}
+	return name
+}
+
+// Types returns the types in the registry, in order.
+func (r *TypeRegistry) Types() []Type {
+	res := make([]Type, len(r.types))
+	copy(res, r.types)
+	return res
+}
+
+// Names returns the canonical names of the types in the registry, in order.
+// Deprecated types are only included if includeDeprecated is true.
+func (r *TypeRegistry) Names(includeDeprecated bool) []string {
+	res := make([]string, 0, len(r.types))
+	for _, t := range r.types {
+		if t.Deprecated && !includeDeprecated {
+			continue
+		}
+		res = append(res, t.Name)
+	}
+	return res
+}
+
+// NotesFromEntry returns the notes in entry, like NotesFromEntry, with type
+// aliases resolved to their canonical names.
+func (r *TypeRegistry) NotesFromEntry(entry Entry) []Note {
+	return (&NoteParser{Types: r}).NotesFromEntry(entry)
+}